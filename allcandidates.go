@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// Candidate is one way of splitting a name, see ParseAllCandidates
+type Candidate struct {
+	Record *Record
+	// Rule is the suffix list rule behind the split, such as "co.uk",
+	// "*.ck" or "!www.ck"
+	Rule string
+	// Private is set when Rule is in the private section of the list
+	Private bool
+	// Prevailing is set for the split Parse returns
+	Prevailing bool
+}
+
+// ParseAllCandidates returns every split of host that a rule of the list
+// supports, the one Parse returns first and then the others from the most
+// to the least specific suffix. "shop.example.blogspot.com" yields the
+// tenant view with TLD "blogspot.com" and the provider view with TLD "com".
+// Wildcard matches overruled by an exception are left out, as are splits
+// that leave no name. Reverse DNS and special-use names have a single
+// candidate. It fails with the error of Parse if host does not parse.
+func (d *Domain) ParseAllCandidates(host string) ([]Candidate, error) {
+	rec, err := d.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+	if rec.IP != nil || rec.SpecialUse != "" {
+		return []Candidate{{Record: rec, Prevailing: true}}, nil
+	}
+	var full []string
+	full = append(full, rec.ServiceLabels...)
+	if rec.Subdomain != "" {
+		full = append(full, strings.Split(rec.Subdomain, ".")...)
+	}
+	full = append(full, rec.Name)
+	full = append(full, strings.Split(rec.TLD, ".")...)
+	labels := make([]string, len(full))
+	for i, label := range full {
+		labels[i] = foldCase(label, d.opts.casePolicy)
+	}
+	prevailing := len(full) - strings.Count(rec.TLD, ".") - 1
+
+	rules := d.snapshot().store
+	var candidates []Candidate
+	seen := make(map[int]bool)
+	for i := range labels {
+		candidate := strings.Join(labels[i:], ".")
+		var rule string
+		start := i
+		switch {
+		case rules.exists("!" + candidate):
+			rule, start = "!"+candidate, i+1
+		case rules.exists(candidate):
+			rule = candidate
+		case i+1 < len(labels) && rules.exists("*."+strings.Join(labels[i+1:], ".")):
+			rule = "*." + strings.Join(labels[i+1:], ".")
+		default:
+			continue
+		}
+		if start == 0 || start == len(labels) || seen[start] {
+			continue
+		}
+		seen[start] = true
+		c := rec.clone()
+		c.Warnings = rec.Warnings
+		c.TLD = strings.Join(full[start:], ".")
+		c.Name = full[start-1]
+		c.Subdomain = strings.Join(full[:start-1], ".")
+		c.ServiceLabels = nil
+		if d.opts.serviceLabels {
+			c.ServiceLabels, c.Subdomain = splitServiceLabels(c.Subdomain)
+		}
+		c.AltRoot = d.altRoot(rule)
+		candidates = append(candidates, Candidate{Record: c, Rule: rule, Private: d.isPrivate(rule), Prevailing: start == prevailing})
+	}
+	if !seen[prevailing] {
+		// the split came from WithUnknownTLDFallback
+		candidates = append(candidates, Candidate{Record: rec, Prevailing: true})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Prevailing != candidates[j].Prevailing {
+			return candidates[i].Prevailing
+		}
+		return strings.Count(candidates[i].Record.TLD, ".") > strings.Count(candidates[j].Record.TLD, ".")
+	})
+	return candidates, nil
+}
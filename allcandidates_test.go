@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAllCandidates(t *testing.T) {
+	list := "// ===BEGIN ICANN DOMAINS===\ncom\nuk\nco.uk\nck\n*.ck\n!www.ck\n// ===END ICANN DOMAINS===\n// ===BEGIN PRIVATE DOMAINS===\nblogspot.com\n// ===END PRIVATE DOMAINS===\n"
+	d, err := NewFromList(strings.NewReader(list), WithServiceLabels())
+	assert.NoError(t, err)
+
+	c, err := d.ParseAllCandidates("_dmarc.shop.example.blogspot.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []Candidate{
+		{Record: &Record{Subdomain: "shop", Name: "example", TLD: "blogspot.com", ServiceLabels: []string{"_dmarc"}}, Rule: "blogspot.com", Private: true, Prevailing: true},
+		{Record: &Record{Subdomain: "shop.example", Name: "blogspot", TLD: "com", ServiceLabels: []string{"_dmarc"}}, Rule: "com"},
+	}, c)
+
+	c, err = d.ParseAllCandidates("www.example.co.uk")
+	assert.NoError(t, err)
+	if assert.Len(t, c, 2) {
+		assert.Equal(t, "co.uk", c[0].Record.TLD)
+		assert.True(t, c[0].Prevailing)
+		assert.Equal(t, &Record{Subdomain: "www.example", Name: "co", TLD: "uk"}, c[1].Record)
+	}
+
+	c, err = d.ParseAllCandidates("a.www.ck")
+	assert.NoError(t, err)
+	assert.Equal(t, []Candidate{
+		{Record: &Record{Subdomain: "a", Name: "www", TLD: "ck"}, Rule: "!www.ck", Prevailing: true},
+	}, c)
+
+	c, err = d.ParseAllCandidates("a.b.example.ck")
+	assert.NoError(t, err)
+	if assert.Len(t, c, 2) {
+		assert.Equal(t, "*.ck", c[0].Rule)
+		assert.Equal(t, "example.ck", c[0].Record.TLD)
+		assert.Equal(t, "ck", c[1].Rule)
+	}
+
+	_, err = d.ParseAllCandidates("example.nonexist")
+	assert.Error(t, err)
+}
@@ -0,0 +1,52 @@
+package domain
+
+// RulePack is a set of suffixes of an alternative naming system outside the
+// ICANN root, such as ENS. Packs are off by default, see WithRulePacks.
+type RulePack struct {
+	// Name identifies the pack in Record.AltRoot and in Resolve, it must not
+	// clash with the name of another source
+	Name  string
+	Rules []string
+}
+
+// Bundled rule packs
+var (
+	// ENSPack holds the Ethereum Name Service root
+	ENSPack = RulePack{Name: "ens", Rules: []string{"eth"}}
+	// UnstoppablePack holds the best known Unstoppable Domains endings
+	UnstoppablePack = RulePack{Name: "unstoppable", Rules: []string{
+		"crypto", "nft", "wallet", "blockchain", "bitcoin", "dao", "888", "zil", "x", "polygon", "klever", "unstoppable",
+	}}
+	// HandshakePack holds a few widely used Handshake TLDs, Handshake has no
+	// fixed list so callers usually extend it with the names they see
+	HandshakePack = RulePack{Name: "handshake", Rules: []string{"hns", "forever", "nb", "c", "d"}}
+)
+
+// WithRulePacks adds the suffixes of alternative roots, so names such as
+// "vitalik.eth" parse instead of failing with an unknown TLD. Records under
+// a pack suffix carry the pack name in Record.AltRoot. The packs are merged
+// like sources with a priority below the public suffix list, which keeps
+// winning for any suffix ICANN delegates.
+func WithRulePacks(packs ...RulePack) Option {
+	return func(o *options) {
+		if o.rulePacks == nil {
+			o.rulePacks = make(map[string]bool)
+		}
+		for _, p := range packs {
+			o.rulePacks[p.Name] = true
+			o.sources = append(o.sources, Source{Name: p.Name, Priority: -1, Rules: p.Rules})
+		}
+	}
+}
+
+// altRoot returns the name of the rule pack that provided rule, or ""
+func (d *Domain) altRoot(rule string) string {
+	if d.opts.rulePacks == nil {
+		return ""
+	}
+	source := d.snapshot().resolutions[rule].Source
+	if d.opts.rulePacks[source] {
+		return source
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRulePacks(t *testing.T) {
+	list := "com\nx\n"
+	plain, _ := NewFromList(strings.NewReader(list))
+	_, err := plain.Parse("vitalik.eth")
+	assert.Error(t, err)
+
+	d, err := NewFromList(strings.NewReader(list), WithRulePacks(ENSPack, UnstoppablePack))
+	assert.NoError(t, err)
+	tests := []struct {
+		i string
+		o *Record
+	}{
+		{i: "app.vitalik.eth", o: &Record{Subdomain: "app", Name: "vitalik", TLD: "eth", AltRoot: "ens"}},
+		{i: "brad.crypto", o: &Record{Name: "brad", TLD: "crypto", AltRoot: "unstoppable"}},
+		{i: "example.x", o: &Record{Name: "example", TLD: "x"}},
+		{i: "www.example.com", o: &Record{Subdomain: "www", Name: "example", TLD: "com"}},
+	}
+	for _, ts := range tests {
+		r, err := d.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+	}
+	res, ok := d.Resolve("x")
+	assert.True(t, ok)
+	assert.Equal(t, Resolution{Rule: "x", Source: PublicSuffixSource, Overridden: []string{"unstoppable"}}, res)
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrCacheMiss is returned by a CacheBackend that holds no suffix list yet
+var ErrCacheMiss = errors.New("cache: no suffix list stored")
+
+// CacheBackend stores the cached suffix list somewhere other than the cache
+// file given to New, such as a database an application already embeds. The
+// list is stored as a single blob in the cache format, so the rules and the
+// version metadata recorded with them are always replaced together.
+type CacheBackend interface {
+	// Load returns the stored list and when it was stored, or ErrCacheMiss
+	Load() (list []byte, stored time.Time, err error)
+	// Store atomically replaces the stored list
+	Store(list []byte) error
+}
+
+// WithCacheBackend keeps the suffix list in backend instead of a cache file,
+// New must then be called with an empty cache file name. The list is
+// downloaded when the backend is empty or older than the refresh interval.
+func WithCacheBackend(backend CacheBackend) Option {
+	return func(o *options) {
+		o.backend = backend
+	}
+}
+
+// loadBackend returns the list held by the backend of o, downloading and
+// storing a new one when it is missing or expired
+func loadBackend(o *options) ([]byte, error) {
+	list, stored, err := o.backend.Load()
+	if err != nil && !errors.Is(err, ErrCacheMiss) {
+		return nil, fmt.Errorf("Could not load cache: %v", err)
+	}
+	if err == nil && (o.refreshInterval <= 0 || time.Since(stored) <= o.refreshInterval) {
+		return list, nil
+	}
+	if o.offline {
+		if err == nil {
+			return list, nil
+		}
+		return nil, fmt.Errorf("Could not load cache: backend is empty and offline mode is enabled")
+	}
+	var buf bytes.Buffer
+	if err := downloadList(&buf, o); err != nil {
+		return nil, err
+	}
+	if err := o.backend.Store(buf.Bytes()); err != nil {
+		return nil, fmt.Errorf("Could not store cache: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// FileBackend is the CacheBackend of a cache file, with the same locking as
+// the cache file given to New
+type FileBackend struct {
+	Path string
+}
+
+// Load reads the cache file under a shared lock
+func (f FileBackend) Load() ([]byte, time.Time, error) {
+	info, err := os.Stat(f.Path)
+	if os.IsNotExist(err) {
+		return nil, time.Time{}, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	list, err := readCache(f.Path)
+	return list, info.ModTime(), err
+}
+
+// Store replaces the cache file under an exclusive lock
+func (f FileBackend) Store(list []byte) error {
+	lock, err := lockCache(f.Path, true)
+	if err != nil {
+		return err
+	}
+	defer lock.unlock()
+	return writeCache(f.Path, list, false)
+}
+
+// SQLBackend stores the suffix list in a single row of a database/sql table,
+// for applications that already embed SQLite or a similar database. The
+// queries use "?" placeholders.
+type SQLBackend struct {
+	db    *sql.DB
+	table string
+}
+
+// NewSQLBackend returns a backend keeping the list in table, which is created
+// if it does not exist. table must be a plain SQL identifier.
+func NewSQLBackend(db *sql.DB, table string) (*SQLBackend, error) {
+	if !validIdentifier(table) {
+		return nil, fmt.Errorf("cache: invalid table name \"%s\"", table)
+	}
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS " + table + " (id INTEGER PRIMARY KEY, list BLOB NOT NULL, stored INTEGER NOT NULL)")
+	if err != nil {
+		return nil, fmt.Errorf("cache: %v", err)
+	}
+	return &SQLBackend{db: db, table: table}, nil
+}
+
+// Load reads the stored list
+func (s *SQLBackend) Load() ([]byte, time.Time, error) {
+	var list []byte
+	var stored int64
+	err := s.db.QueryRow("SELECT list, stored FROM "+s.table+" WHERE id = 1").Scan(&list, &stored)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return list, time.Unix(stored, 0), nil
+}
+
+// Store replaces the stored list in a single transaction
+func (s *SQLBackend) Store(list []byte) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM " + s.table); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO "+s.table+" (id, list, stored) VALUES (1, ?, ?)", list, time.Now().Unix()); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// validIdentifier checks that s is a letter or underscore followed by
+// letters, digits and underscores
+func validIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		case i > 0 && c >= '0' && c <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
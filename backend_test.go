@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memBackend is a CacheBackend kept in memory
+type memBackend struct {
+	list   []byte
+	stored time.Time
+	stores int
+}
+
+func (m *memBackend) Load() ([]byte, time.Time, error) {
+	if m.list == nil {
+		return nil, time.Time{}, ErrCacheMiss
+	}
+	return m.list, m.stored, nil
+}
+
+func (m *memBackend) Store(list []byte) error {
+	m.list, m.stored = list, time.Now()
+	m.stores++
+	return nil
+}
+
+func TestCacheBackend(t *testing.T) {
+	list := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "// VERSION: 1\ncom\n")
+	}))
+	defer list.Close()
+	listURL := func(o *options) { o.listURL = list.URL }
+
+	b := &memBackend{}
+	_, err := New("", WithCacheBackend(b), WithOffline())
+	assert.Error(t, err)
+
+	d, err := New("", WithCacheBackend(b), listURL)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, b.stores)
+	assert.Equal(t, "1", d.ListVersion().Version)
+	_, err = d.Parse("example.com")
+	assert.NoError(t, err)
+
+	// a stored list is used as long as it is fresh
+	b.list = []byte("// VERSION: 2\ncom\nnet\n")
+	d, err = New("", WithCacheBackend(b), WithRefreshInterval(time.Hour), listURL)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, b.stores)
+	assert.Equal(t, "2", d.ListVersion().Version)
+	assert.NoError(t, d.Refresh())
+	assert.Equal(t, 2, b.stores)
+	assert.Equal(t, "1", d.ListVersion().Version)
+	assert.NoError(t, d.Reload())
+}
+
+func TestFileBackend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	f := FileBackend{Path: filepath.Join(dir, "tld.cache")}
+
+	_, _, err = f.Load()
+	assert.Equal(t, ErrCacheMiss, err)
+	assert.NoError(t, f.Store([]byte("com\n")))
+	list, stored, err := f.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "com\n", string(list))
+	assert.WithinDuration(t, time.Now(), stored, time.Minute)
+
+	assert.True(t, validIdentifier("suffix_list2"))
+	assert.False(t, validIdentifier("list; DROP TABLE x"))
+}
+
+// sqlStub is a database/sql driver holding a single row, it understands only
+// the statements SQLBackend issues and records every one of them
+type sqlStub struct {
+	mu         sync.Mutex
+	statements []string
+	row        []driver.Value
+	commits    int
+}
+
+func (s *sqlStub) Connect(ctx context.Context) (driver.Conn, error) { return sqlStubConn{s}, nil }
+func (s *sqlStub) Driver() driver.Driver                            { return s }
+func (s *sqlStub) Open(name string) (driver.Conn, error)            { return sqlStubConn{s}, nil }
+
+type sqlStubConn struct{ s *sqlStub }
+
+func (c sqlStubConn) Prepare(query string) (driver.Stmt, error) { return sqlStubStmt{c.s, query}, nil }
+func (c sqlStubConn) Close() error                              { return nil }
+func (c sqlStubConn) Begin() (driver.Tx, error)                 { return c, nil }
+func (c sqlStubConn) Rollback() error                           { return nil }
+
+func (c sqlStubConn) Commit() error {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	c.s.commits++
+	return nil
+}
+
+type sqlStubStmt struct {
+	s     *sqlStub
+	query string
+}
+
+func (st sqlStubStmt) Close() error  { return nil }
+func (st sqlStubStmt) NumInput() int { return strings.Count(st.query, "?") }
+
+func (st sqlStubStmt) Exec(args []driver.Value) (driver.Result, error) {
+	st.s.mu.Lock()
+	defer st.s.mu.Unlock()
+	st.s.statements = append(st.s.statements, st.query)
+	switch {
+	case strings.HasPrefix(st.query, "DELETE"):
+		st.s.row = nil
+	case strings.HasPrefix(st.query, "INSERT"):
+		st.s.row = args
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (st sqlStubStmt) Query(args []driver.Value) (driver.Rows, error) {
+	st.s.mu.Lock()
+	defer st.s.mu.Unlock()
+	st.s.statements = append(st.s.statements, st.query)
+	return &sqlStubRows{row: st.s.row}, nil
+}
+
+type sqlStubRows struct {
+	row  []driver.Value
+	done bool
+}
+
+func (r *sqlStubRows) Columns() []string { return []string{"list", "stored"} }
+func (r *sqlStubRows) Close() error      { return nil }
+
+func (r *sqlStubRows) Next(dest []driver.Value) error {
+	if r.row == nil || r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.row)
+	return nil
+}
+
+func TestSQLBackend(t *testing.T) {
+	stub := &sqlStub{}
+	db := sql.OpenDB(stub)
+	defer db.Close()
+
+	_, err := NewSQLBackend(db, "psl; DROP TABLE users")
+	assert.Error(t, err)
+	b, err := NewSQLBackend(db, "psl_cache")
+	assert.NoError(t, err)
+	_, _, err = b.Load()
+	assert.Equal(t, ErrCacheMiss, err)
+
+	assert.NoError(t, b.Store([]byte("// VERSION: 1\ncom\n")))
+	list, stored, err := b.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, "// VERSION: 1\ncom\n", string(list))
+	assert.WithinDuration(t, time.Now(), stored, time.Minute)
+	assert.Equal(t, 1, stub.commits)
+	assert.Equal(t, []string{
+		"CREATE TABLE IF NOT EXISTS psl_cache (id INTEGER PRIMARY KEY, list BLOB NOT NULL, stored INTEGER NOT NULL)",
+		"SELECT list, stored FROM psl_cache WHERE id = 1",
+		"DELETE FROM psl_cache",
+		"INSERT INTO psl_cache (id, list, stored) VALUES (1, ?, ?)",
+		"SELECT list, stored FROM psl_cache WHERE id = 1",
+	}, stub.statements)
+
+	// a Domain loads the stored list without downloading it
+	d, err := New("", WithCacheBackend(b), WithOffline())
+	assert.NoError(t, err)
+	assert.Equal(t, "1", d.ListVersion().Version)
+	_, err = d.Parse("example.com")
+	assert.NoError(t, err)
+}
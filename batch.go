@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+	"time"
+)
+
+// BatchResult is the outcome of parsing one host of a batch
+type BatchResult struct {
+	Host   string
+	Record *Record
+	Err    error
+}
+
+// BatchStats summarises a batch or stream run
+type BatchStats struct {
+	Parsed  int
+	Failed  int
+	Elapsed time.Duration
+	Latency Histogram
+}
+
+// String formats the stats as a single line suitable for logs
+func (s BatchStats) String() string {
+	return fmt.Sprintf("parsed=%d failed=%d elapsed=%s p50=%s p95=%s p99=%s max=%s",
+		s.Parsed, s.Failed, s.Elapsed,
+		s.Latency.Quantile(0.50), s.Latency.Quantile(0.95), s.Latency.Quantile(0.99), s.Latency.Max())
+}
+
+// ParseBatch parses every host and returns the results in input order
+func (d *Domain) ParseBatch(hosts []string) ([]BatchResult, BatchStats) {
+	results := make([]BatchResult, 0, len(hosts))
+	var stats BatchStats
+	start := time.Now()
+	for _, host := range hosts {
+		results = append(results, d.parseTimed(host, &stats))
+	}
+	stats.Elapsed = time.Since(start)
+	return results, stats
+}
+
+// ParseStream parses one host per line from r and hands each result to fn.
+// Blank lines are skipped. The returned error only reports read failures,
+// parse failures are delivered to fn and counted in the stats.
+func (d *Domain) ParseStream(r io.Reader, fn func(BatchResult)) (BatchStats, error) {
+	var stats BatchStats
+	start := time.Now()
+	scan := bufio.NewScanner(r)
+	for scan.Scan() {
+		host := strings.TrimSpace(scan.Text())
+		if host == "" {
+			continue
+		}
+		fn(d.parseTimed(host, &stats))
+	}
+	stats.Elapsed = time.Since(start)
+	return stats, scan.Err()
+}
+
+// parseTimed parses host and records the outcome and latency in stats
+func (d *Domain) parseTimed(host string, stats *BatchStats) BatchResult {
+	start := time.Now()
+	rec, err := d.Parse(host)
+	stats.Latency.Observe(time.Since(start))
+	if err != nil {
+		stats.Failed++
+	} else {
+		stats.Parsed++
+	}
+	return BatchResult{Host: host, Record: rec, Err: err}
+}
+
+// histogramSubBuckets is the number of linear buckets per power of two,
+// giving quantiles within 25% of the true value
+const histogramSubBuckets = 4
+
+// Histogram is a fixed size, log-linear latency histogram. The zero value is
+// ready to use.
+type Histogram struct {
+	counts [64 * histogramSubBuckets]uint64
+	total  uint64
+	max    time.Duration
+}
+
+// Observe records one duration
+func (h *Histogram) Observe(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.counts[histogramBucket(uint64(d))]++
+	h.total++
+	if d > h.max {
+		h.max = d
+	}
+}
+
+// Count returns the number of observed durations
+func (h *Histogram) Count() uint64 {
+	return h.total
+}
+
+// Max returns the largest observed duration
+func (h *Histogram) Max() time.Duration {
+	return h.max
+}
+
+// Quantile returns an upper bound for the q-th quantile (0 <= q <= 1) of the
+// observed durations, or 0 if nothing was observed
+func (h *Histogram) Quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(q * float64(h.total))
+	if rank >= h.total {
+		rank = h.total - 1
+	}
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen > rank {
+			upper := time.Duration(histogramUpper(i))
+			if upper > h.max {
+				return h.max
+			}
+			return upper
+		}
+	}
+	return h.max
+}
+
+// Merge adds the observations of other into h
+func (h *Histogram) Merge(other *Histogram) {
+	for i, c := range other.counts {
+		h.counts[i] += c
+	}
+	h.total += other.total
+	if other.max > h.max {
+		h.max = other.max
+	}
+}
+
+// histogramBucket maps a value to its bucket index
+func histogramBucket(v uint64) int {
+	if v < histogramSubBuckets {
+		return int(v)
+	}
+	exp := bits.Len64(v) - 1
+	sub := (v >> uint(exp-2)) & (histogramSubBuckets - 1)
+	return (exp-1)*histogramSubBuckets + int(sub)
+}
+
+// histogramUpper returns the largest value that falls into bucket i
+func histogramUpper(i int) uint64 {
+	if i < histogramSubBuckets {
+		return uint64(i)
+	}
+	exp := uint(i/histogramSubBuckets + 1)
+	sub := uint64(i % histogramSubBuckets)
+	base := uint64(1) << exp
+	step := base / histogramSubBuckets
+	return base + (sub+1)*step - 1
+}
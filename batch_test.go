@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogramQuantile(t *testing.T) {
+	var h Histogram
+	assert.Equal(t, time.Duration(0), h.Quantile(0.5))
+	for i := 1; i <= 100; i++ {
+		h.Observe(time.Duration(i) * time.Microsecond)
+	}
+	assert.Equal(t, uint64(100), h.Count())
+	assert.Equal(t, 100*time.Microsecond, h.Max())
+	tests := []struct {
+		q    float64
+		want time.Duration
+	}{
+		{q: 0.50, want: 50 * time.Microsecond},
+		{q: 0.95, want: 95 * time.Microsecond},
+		{q: 0.99, want: 99 * time.Microsecond},
+		{q: 1, want: 100 * time.Microsecond},
+	}
+	for _, ts := range tests {
+		got := h.Quantile(ts.q)
+		assert.True(t, got >= ts.want, "p%v: %s below %s", ts.q*100, got, ts.want)
+		assert.True(t, got <= ts.want*5/4, "p%v: %s too far above %s", ts.q*100, got, ts.want)
+	}
+}
+
+func TestParseStream(t *testing.T) {
+	d := newDomain("", strings.NewReader("com\n"), &options{})
+	var hosts []string
+	stats, err := d.ParseStream(strings.NewReader("www.example.com\n\nbad\nexample.com\n"), func(r BatchResult) {
+		hosts = append(hosts, r.Host)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"www.example.com", "bad", "example.com"}, hosts)
+	assert.Equal(t, 2, stats.Parsed)
+	assert.Equal(t, 1, stats.Failed)
+	assert.Equal(t, uint64(3), stats.Latency.Count())
+}
@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BrandMatchType is how a label matched a brand, from strongest to weakest
+type BrandMatchType int
+
+const (
+	// BrandExact means the label is the brand
+	BrandExact BrandMatchType = iota
+	// BrandSubstring means the label contains the brand, "paypal-login"
+	BrandSubstring
+	// BrandLeet means the label contains the brand spelled with digits or
+	// symbols for letters, "p4ypa1"
+	BrandLeet
+	// BrandHomoglyph means the label contains a lookalike of the brand built
+	// from confusable characters, "pаypal" with a Cyrillic "а"
+	BrandHomoglyph
+)
+
+// String returns the name of the match type
+func (t BrandMatchType) String() string {
+	switch t {
+	case BrandExact:
+		return "exact"
+	case BrandSubstring:
+		return "substring"
+	case BrandLeet:
+		return "leet"
+	case BrandHomoglyph:
+		return "homoglyph"
+	}
+	return "unknown"
+}
+
+// BrandLocation is the part of a record a brand was found in
+type BrandLocation int
+
+const (
+	// InName means the brand was found in the registrable name
+	InName BrandLocation = iota
+	// InSubdomain means the brand was found in a subdomain label
+	InSubdomain
+)
+
+// String returns the name of the location
+func (l BrandLocation) String() string {
+	if l == InSubdomain {
+		return "subdomain"
+	}
+	return "name"
+}
+
+// BrandMatch is one brand found in a record
+type BrandMatch struct {
+	Brand    string
+	Label    string
+	Location BrandLocation
+	Type     BrandMatchType
+}
+
+// BrandWatchlist matches records against a set of protected brand terms,
+// the certificate transparency monitoring workflow. It is safe for
+// concurrent use.
+type BrandWatchlist struct {
+	mu     sync.RWMutex
+	brands map[string]brandTerm
+}
+
+// brandTerm caches the normalized forms of a brand
+type brandTerm struct {
+	brand, skeleton string
+}
+
+// NewBrandWatchlist creates a watchlist holding brands
+func NewBrandWatchlist(brands ...string) *BrandWatchlist {
+	w := &BrandWatchlist{brands: make(map[string]brandTerm)}
+	w.Add(brands...)
+	return w
+}
+
+// Add registers more brand terms, they are matched case-insensitively
+func (w *BrandWatchlist) Add(brands ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, b := range brands {
+		b = strings.ToLower(strings.TrimSpace(b))
+		if b != "" {
+			w.brands[b] = brandTerm{brand: b, skeleton: Skeleton(b)}
+		}
+	}
+}
+
+// MatchBrand returns one match for every brand found in the record's name
+// and subdomain labels: the one of the strongest type, in the name when the
+// brand matches as strongly there as in a subdomain. Name matches come first.
+func (w *BrandWatchlist) MatchBrand(r *Record) []BrandMatch {
+	labels := []string{strings.ToLower(r.Name)}
+	if r.Subdomain != "" {
+		labels = append(labels, strings.Split(strings.ToLower(r.Subdomain), ".")...)
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	best := make(map[string]BrandMatch)
+	for i, label := range labels {
+		loc := InName
+		if i > 0 {
+			loc = InSubdomain
+		}
+		label = unicodeName(label)
+		var leet []string
+		var skeleton string
+		for _, term := range w.brands {
+			t, ok := matchBrandLabel(term, label, &leet, &skeleton)
+			if !ok {
+				continue
+			}
+			// labels are visited name first, so a tie on type keeps the name
+			if prev, seen := best[term.brand]; !seen || t < prev.Type {
+				best[term.brand] = BrandMatch{Brand: term.brand, Label: label, Location: loc, Type: t}
+			}
+		}
+	}
+	var matches []BrandMatch
+	for _, m := range best {
+		matches = append(matches, m)
+	}
+	sortBrandMatches(matches)
+	return matches
+}
+
+// matchBrandLabel finds the strongest way label matches term, the leet and
+// skeleton forms of label are computed on first use
+func matchBrandLabel(term brandTerm, label string, leet *[]string, skeleton *string) (BrandMatchType, bool) {
+	if label == term.brand {
+		return BrandExact, true
+	}
+	if strings.Contains(label, term.brand) {
+		return BrandSubstring, true
+	}
+	if *leet == nil {
+		*leet = deleet(label)
+	}
+	for _, l := range *leet {
+		if strings.Contains(l, term.brand) {
+			return BrandLeet, true
+		}
+	}
+	if *skeleton == "" {
+		*skeleton = Skeleton(label)
+	}
+	if strings.Contains(*skeleton, term.skeleton) {
+		return BrandHomoglyph, true
+	}
+	return 0, false
+}
+
+// leet substitutions, "1" is ambiguous and tried as both "i" and "l"
+var (
+	leetI = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "8", "b", "9", "g", "@", "a", "$", "s", "!", "i")
+	leetL = strings.NewReplacer("0", "o", "1", "l", "3", "e", "4", "a", "5", "s", "7", "t", "8", "b", "9", "g", "@", "a", "$", "s", "!", "i")
+)
+
+// deleet returns label with leet substitutions undone, or an empty list
+// when label has none
+func deleet(label string) []string {
+	i, l := leetI.Replace(label), leetL.Replace(label)
+	if i == label {
+		return []string{}
+	}
+	if i == l {
+		return []string{i}
+	}
+	return []string{i, l}
+}
+
+// sortBrandMatches orders matches by location, then type, then brand
+func sortBrandMatches(m []BrandMatch) {
+	sort.Slice(m, func(i, j int) bool {
+		a, b := m[i], m[j]
+		if a.Location != b.Location {
+			return a.Location < b.Location
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Brand != b.Brand {
+			return a.Brand < b.Brand
+		}
+		return a.Label < b.Label
+	})
+}
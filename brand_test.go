@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrandWatchlist(t *testing.T) {
+	w := NewBrandWatchlist("PayPal", "acme")
+	ex := newTestDomain(t)
+	tests := []struct {
+		host string
+		o    []BrandMatch
+	}{
+		{host: "paypal.com", o: []BrandMatch{{Brand: "paypal", Label: "paypal", Location: InName, Type: BrandExact}}},
+		{host: "secure-paypal-login.com", o: []BrandMatch{{Brand: "paypal", Label: "secure-paypal-login", Location: InName, Type: BrandSubstring}}},
+		{host: "p4ypa1.net", o: []BrandMatch{{Brand: "paypal", Label: "p4ypa1", Location: InName, Type: BrandLeet}}},
+		{host: "pаypal.com", o: []BrandMatch{{Brand: "paypal", Label: "pаypal", Location: InName, Type: BrandHomoglyph}}},
+		{host: "acme.paypal.evil.com", o: []BrandMatch{
+			{Brand: "acme", Label: "acme", Location: InSubdomain, Type: BrandExact},
+			{Brand: "paypal", Label: "paypal", Location: InSubdomain, Type: BrandExact},
+		}},
+		{host: "paypal-login.paypal.p4ypal.com", o: []BrandMatch{
+			{Brand: "paypal", Label: "paypal", Location: InSubdomain, Type: BrandExact},
+		}},
+		{host: "paypal.acme.paypal.com", o: []BrandMatch{
+			{Brand: "paypal", Label: "paypal", Location: InName, Type: BrandExact},
+			{Brand: "acme", Label: "acme", Location: InSubdomain, Type: BrandExact},
+		}},
+		{host: "example.com", o: nil},
+	}
+	for _, ts := range tests {
+		r, err := ex.Parse(ts.host)
+		assert.NoError(t, err)
+		assert.Equal(t, ts.o, w.MatchBrand(r), ts.host)
+	}
+
+	w.Add("example")
+	r, _ := ex.Parse("example.com")
+	assert.Equal(t, []BrandMatch{{Brand: "example", Label: "example", Location: InName, Type: BrandExact}}, w.MatchBrand(r))
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DNS length limits of RFC 1035, counted on the ASCII form of a name
+const (
+	maxLabelLength = 63
+	maxNameLength  = 253
+)
+
+// Hostname returns the canonical lowercase hostname of the record
+func (r *Record) Hostname() string {
+	return r.host()
+}
+
+// Build assembles a Record from a subdomain (which may be empty), a single
+// label name and a public suffix. Every label must pass the same checks as
+// Parse plus the DNS length limits, and the result must parse back into
+// exactly the given parts, so tld has to be the public suffix that governs
+// the name.
+func (d *Domain) Build(sub, name, tld string) (*Record, error) {
+	sub = strings.ToLower(strings.Trim(sub, "."))
+	name = strings.ToLower(name)
+	tld = strings.ToLower(strings.Trim(tld, "."))
+	if name == "" {
+		return nil, fmt.Errorf("build: missing domain name")
+	}
+	if strings.ContainsRune(name, '.') {
+		return nil, fmt.Errorf("build: name \"%s\" must be a single label", name)
+	}
+	if tld == "" {
+		return nil, fmt.Errorf("build: missing top level domain")
+	}
+	host := name + "." + tld
+	if sub != "" {
+		host = sub + "." + host
+	}
+	if err := checkLengths(host); err != nil {
+		return nil, fmt.Errorf("build: %v", err)
+	}
+	rec, err := d.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("build: %v", err)
+	}
+	if rec.TLD != tld || rec.Name != name || rec.Subdomain != sub {
+		return nil, fmt.Errorf("build: \"%s\" is not the public suffix of \"%s\", it parses as %s", tld, host, rec.TLD)
+	}
+	return rec, nil
+}
+
+// checkLengths enforces the DNS label and name length limits on host
+func checkLengths(host string) error {
+	ascii, err := toASCII(host)
+	if err != nil {
+		return fmt.Errorf("\"%s\": %v", host, err)
+	}
+	if len(ascii) > maxNameLength {
+		return fmt.Errorf("\"%s\": name is longer than %d characters", host, maxNameLength)
+	}
+	for _, label := range strings.Split(ascii, ".") {
+		if len(label) > maxLabelLength {
+			return fmt.Errorf("\"%s\": label \"%s\" is longer than %d characters", host, label, maxLabelLength)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainBuild(t *testing.T) {
+	tests := []struct {
+		sub, name, tld string
+		o              *Record
+		host           string
+	}{
+		{sub: "WWW", name: "Example", tld: "com", o: &Record{Subdomain: "www", Name: "example", TLD: "com"}, host: "www.example.com"},
+		{sub: "", name: "example", tld: "co.uk", o: &Record{Name: "example", TLD: "co.uk"}, host: "example.co.uk"},
+		{sub: "a.b", name: "test", tld: "kobe.jp", o: nil},
+		{sub: "", name: "www", tld: "ck", o: &Record{Name: "www", TLD: "ck"}, host: "www.ck"},
+		{sub: "", name: "example", tld: "ck"},
+		{sub: "", name: "co", tld: "uk"},
+		{sub: "", name: "", tld: "com"},
+		{sub: "", name: "a.b", tld: "com"},
+		{sub: "", name: "example", tld: "nonexist"},
+		{sub: "", name: "exa mple", tld: "com"},
+		{sub: strings.Repeat("a", 64), name: "example", tld: "com"},
+		{sub: strings.Repeat("abcdefghi.", 25), name: "example", tld: "com"},
+	}
+	ex := newTestDomain(t)
+	for _, ts := range tests {
+		r, err := ex.Build(ts.sub, ts.name, ts.tld)
+		if ts.o == nil {
+			assert.Error(t, err, "%s|%s|%s", ts.sub, ts.name, ts.tld)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, ts.o, r)
+		assert.Equal(t, ts.host, r.Hostname())
+	}
+}
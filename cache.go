@@ -0,0 +1,346 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// A cache file can be shared by any number of processes on one host, such as
+// a CLI run alongside a long lived daemon. They cooperate through an advisory
+// lock on a sibling ".lock" file:
+//
+//   - writers (creation, Refresh) hold the lock exclusively while they
+//     download the list, write it to a temporary file and rename it over the
+//     cache, so the cache is always either the complete old or new list
+//   - readers (New, Reload) hold the lock shared while they read the cache,
+//     which keeps a replace from racing a read on platforms where an open
+//     file cannot be renamed over
+//   - long running readers notice a replaced cache with Watch and reload it
+
+// ensureCache makes sure a usable cache file exists, downloading it when it is
+// missing or expired. Processes started together download the list once.
+func ensureCache(cacheFile string, o *options) error {
+	return ensureFile(cacheFile, o, downloadList)
+}
+
+// ensureFile makes sure file exists and is fresh, writing the output of
+// download to it under the cache lock otherwise
+func ensureFile(cacheFile string, o *options, download func(io.Writer, *options) error) error {
+	if cacheExists(cacheFile) && !cacheExpired(cacheFile, o.refreshInterval) {
+		return nil
+	}
+	if o.offline {
+		return fmt.Errorf("Could not open cache file: %s does not exist and offline mode is enabled", cacheFile)
+	}
+	lock, err := lockCache(cacheFile, true)
+	if err != nil {
+		return err
+	}
+	defer lock.unlock()
+	// another process may have written the cache while we waited for the lock
+	if cacheExists(cacheFile) && !cacheExpired(cacheFile, o.refreshInterval) {
+		return nil
+	}
+	return newCache(cacheFile, o, download)
+}
+
+// readCache reads the whole cache file under a shared lock
+func readCache(cacheFile string) ([]byte, error) {
+	lock, err := lockCache(cacheFile, false)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.unlock()
+	list, err := ioutil.ReadFile(cacheFile)
+	if err != nil {
+		return nil, fmt.Errorf("Could not open cache file: %v", err)
+	}
+	return decompressCache(list)
+}
+
+// gzipMagic starts every gzip stream, a suffix list never does
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decompressCache returns the contents of a cache file, decompressing it when
+// it was written with WithCompressedCache
+func decompressCache(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("Could not decompress cache file: %v", err)
+	}
+	list, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("Could not decompress cache file: %v", err)
+	}
+	return list, nil
+}
+
+// compressCache gzips data at the best compression level
+func compressCache(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// newCache downloads a list with download and creates a new cache file
+func newCache(cacheFile string, o *options, download func(io.Writer, *options) error) error {
+	var list bytes.Buffer
+	if err := download(&list, o); err != nil {
+		return err
+	}
+	return writeCache(cacheFile, list.Bytes(), o.compressCache)
+}
+
+// writeCache atomically replaces cacheFile with data, gzipped if compress is
+// set, readers see either the old or the new file but never a partially
+// written one
+func writeCache(cacheFile string, data []byte, compress bool) error {
+	if compress {
+		var err error
+		if data, err = compressCache(data); err != nil {
+			return fmt.Errorf("Could not compress cache file: %v", err)
+		}
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(cacheFile), filepath.Base(cacheFile)+".tmp")
+	if err != nil {
+		return fmt.Errorf("Could not create new cache file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Could not write cache file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Could not write cache file: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("Could not write cache file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), cacheFile); err != nil {
+		return fmt.Errorf("Could not replace cache file: %v", err)
+	}
+	return nil
+}
+
+// DefaultListURL is where the public suffix list is downloaded from
+const DefaultListURL = "https://publicsuffix.org/list/public_suffix_list.dat"
+
+// GitHubMirror is the copy of the list kept in the upstream git repository,
+// suitable for WithMirrors
+const GitHubMirror = "https://raw.githubusercontent.com/publicsuffix/list/master/public_suffix_list.dat"
+
+// Downloader fetches the raw suffix list, in the publicsuffix.org format.
+// Pass one to WithDownloader to reuse an existing HTTP stack, or to serve a
+// canned list in tests.
+type Downloader interface {
+	Fetch(ctx context.Context) (io.ReadCloser, error)
+}
+
+// HTTPDownloader downloads a list with a GET request to URL
+type HTTPDownloader struct {
+	URL string
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+}
+
+// Fetch sends the request and returns the response body
+func (h HTTPDownloader) Fetch(ctx context.Context) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: unexpected status %s", h.URL, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// downloadList fetches the TLD suffix list and writes one suffix per line to
+// w, preceded by comment lines recording the list version and download time.
+// Each attempt tries the list URL and then every mirror in order, or the
+// Downloader given to WithDownloader, failed attempts are retried after an
+// exponentially growing backoff.
+func downloadList(w io.Writer, o *options) error {
+	var sources []Downloader
+	if o.downloader != nil {
+		sources = []Downloader{o.downloader}
+	} else {
+		client := o.httpClient()
+		for _, u := range append([]string{o.listURL}, o.mirrors...) {
+			sources = append(sources, HTTPDownloader{URL: u, Client: client})
+		}
+	}
+	err := downloadFrom(w, o, sources, func(w io.Writer, r io.Reader) error {
+		if len(o.verifiers) == 0 && o.checksumURL == "" {
+			return writeList(w, r, time.Now())
+		}
+		list, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if err := o.verifyList(context.Background(), list); err != nil {
+			return err
+		}
+		return writeList(w, bytes.NewReader(list), time.Now())
+	})
+	if err != nil {
+		return fmt.Errorf("Could not download suffix list: %w", err)
+	}
+	return nil
+}
+
+// downloadFrom tries every source in order, retrying with backoff, and
+// writes the first successful response to w through convert
+func downloadFrom(w io.Writer, o *options, sources []Downloader, convert func(io.Writer, io.Reader) error) error {
+	backoff := o.retryBackoff
+	var errs downloadErrors
+	for attempt := 0; ; attempt++ {
+		for _, src := range sources {
+			var list bytes.Buffer
+			err := fetch(&list, src, convert)
+			if err == nil {
+				_, err = list.WriteTo(w)
+				return err
+			}
+			errs = append(errs, err)
+		}
+		if attempt >= o.retries {
+			break
+		}
+		o.sleep(backoff)
+		backoff *= 2
+	}
+	return errs
+}
+
+// downloadErrors are the failures of every download attempt, errors.As
+// finds a *ChecksumError among them
+type downloadErrors []error
+
+func (e downloadErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e downloadErrors) Unwrap() []error {
+	return e
+}
+
+// fetch downloads from a single source and writes the body to w through
+// convert
+func fetch(w io.Writer, src Downloader, convert func(io.Writer, io.Reader) error) error {
+	body, err := src.Fetch(context.Background())
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return convert(w, body)
+}
+
+// writeList converts a raw suffix list into the cache format
+func writeList(w io.Writer, list io.Reader, downloaded time.Time) error {
+	var rules bytes.Buffer
+	version := ListVersion{Downloaded: downloaded.UTC().Truncate(time.Second)}
+	scan := bufio.NewScanner(list)
+	for scan.Scan() {
+		line := scan.Text()
+		if _, ok := sectionMarker(line); ok && strings.HasPrefix(line, "//") {
+			rules.WriteString(line)
+			rules.WriteString("\n")
+		} else if strings.HasPrefix(line, "//") {
+			version.parseHeader(line)
+		} else if line != "" {
+			rules.WriteString(line)
+			rules.WriteString("\n")
+		}
+	}
+	if err := scan.Err(); err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(w)
+	version.writeHeader(buf)
+	if _, err := rules.WriteTo(buf); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+// cacheExists checks if a file exists
+func cacheExists(cacheFile string) bool {
+	if _, err := os.Stat(cacheFile); err == nil {
+		return true
+	} else if os.IsNotExist(err) {
+		return false
+	}
+	return false
+}
+
+// cacheExpired checks if a cache file is older than maxAge, a zero maxAge never expires
+func cacheExpired(cacheFile string, maxAge time.Duration) bool {
+	if maxAge <= 0 {
+		return false
+	}
+	info, err := os.Stat(cacheFile)
+	if err != nil {
+		return false
+	}
+	return time.Since(info.ModTime()) > maxAge
+}
+
+// cacheLock is an advisory lock held on a cache's lock file
+type cacheLock struct {
+	f *os.File
+}
+
+// lockCache blocks until it holds the lock for cacheFile, exclusively for
+// writers or shared for readers. The lock file is left in place afterwards,
+// removing it would let two processes lock different inodes.
+func lockCache(cacheFile string, exclusive bool) (*cacheLock, error) {
+	f, err := os.OpenFile(cacheFile+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("Could not open cache lock: %v", err)
+	}
+	if err := lockFile(f, exclusive); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("Could not lock cache: %v", err)
+	}
+	return &cacheLock{f: f}, nil
+}
+
+// unlock releases the lock and closes the lock file
+func (l *cacheLock) unlock() error {
+	err := unlockFile(l.f)
+	l.f.Close()
+	return err
+}
@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheLockExclusive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cacheFile := filepath.Join(dir, "tld.cache")
+
+	first, err := lockCache(cacheFile, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acquired := make(chan struct{})
+	go func() {
+		second, err := lockCache(cacheFile, false)
+		if err == nil {
+			second.unlock()
+		}
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("second lock acquired while the first was held")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.NoError(t, first.unlock())
+	<-acquired
+}
+
+func TestWriteCacheReplaces(t *testing.T) {
+	dir, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cacheFile := filepath.Join(dir, "tld.cache")
+
+	assert.NoError(t, writeCache(cacheFile, []byte("com\n"), false))
+	assert.NoError(t, writeCache(cacheFile, []byte("com\nnet\n"), false))
+	data, err := ioutil.ReadFile(cacheFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "com\nnet\n", string(data))
+
+	// no temporary files are left behind
+	files, _ := ioutil.ReadDir(dir)
+	assert.Len(t, files, 1)
+}
+
+func TestCompressedCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cacheFile := filepath.Join(dir, "tld.cache")
+
+	list := "// VERSION: gz\n" + strings.Repeat("com\nco.uk\n", 100)
+	assert.NoError(t, writeCache(cacheFile, []byte(list), true))
+	raw, err := ioutil.ReadFile(cacheFile)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(raw, gzipMagic))
+	assert.Less(t, len(raw), len(list)/5)
+
+	data, err := readCache(cacheFile)
+	assert.NoError(t, err)
+	assert.Equal(t, list, string(data))
+
+	// compressed and plain caches load whichever way the option is set
+	d, err := New(cacheFile, WithOffline())
+	assert.NoError(t, err)
+	assert.Equal(t, "gz", d.ListVersion().Version)
+	assert.NoError(t, writeCache(cacheFile, []byte("// VERSION: plain\ncom\n"), false))
+	d, err = New(cacheFile, WithOffline(), WithCompressedCache())
+	assert.NoError(t, err)
+	assert.Equal(t, "plain", d.ListVersion().Version)
+}
+
+func TestDownloadRetriesAndMirrors(t *testing.T) {
+	var primaryHits, mirrorHits int
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrorHits++
+		if mirrorHits < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "// VERSION: mirror\ncom\n")
+	}))
+	defer mirror.Close()
+
+	var waits []time.Duration
+	o := newOptions([]Option{WithRetries(3, time.Second), WithMirrors(mirror.URL)})
+	o.listURL = primary.URL
+	o.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	var list bytes.Buffer
+	assert.NoError(t, downloadList(&list, &o))
+	assert.Contains(t, list.String(), "// VERSION: mirror\n")
+	assert.Equal(t, 3, primaryHits)
+	assert.Equal(t, 3, mirrorHits)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, waits)
+
+	o = newOptions([]Option{WithRetries(1, time.Second)})
+	o.listURL = primary.URL
+	o.sleep = func(time.Duration) {}
+	err := downloadList(&list, &o)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "503 Service Unavailable")
+}
+
+func TestDownloadThroughProxy(t *testing.T) {
+	var requested, auth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.String()
+		auth = r.Header.Get("Proxy-Authorization")
+		fmt.Fprint(w, "com\n")
+	}))
+	defer proxy.Close()
+
+	o := newOptions([]Option{WithProxy("http://agent:secret@" + proxy.Listener.Addr().String())})
+	assert.NoError(t, o.validate("/tmp/tld.cache"))
+	o.listURL = "http://list.example/public_suffix_list.dat"
+	var list bytes.Buffer
+	assert.NoError(t, downloadList(&list, &o))
+	assert.Equal(t, o.listURL, requested)
+	assert.Equal(t, "Basic YWdlbnQ6c2VjcmV0", auth)
+}
+
+type cannedDownloader struct {
+	list  string
+	fails int
+	calls int
+}
+
+func (c *cannedDownloader) Fetch(ctx context.Context) (io.ReadCloser, error) {
+	c.calls++
+	if c.calls <= c.fails {
+		return nil, errors.New("connection refused")
+	}
+	return ioutil.NopCloser(strings.NewReader(c.list)), nil
+}
+
+func TestDownloader(t *testing.T) {
+	dl := &cannedDownloader{list: "// VERSION: canned\ncom\nco.uk\n", fails: 1}
+	o := newOptions([]Option{WithDownloader(dl), WithRetries(1, time.Second)})
+	o.sleep = func(time.Duration) {}
+	var list bytes.Buffer
+	assert.NoError(t, downloadList(&list, &o))
+	assert.Contains(t, list.String(), "// VERSION: canned\n")
+	assert.Equal(t, 2, dl.calls)
+
+	d, err := New("", WithInMemory(), WithDownloader(&cannedDownloader{list: "com\nco.uk\n"}))
+	assert.NoError(t, err)
+	r, err := d.Parse("www.example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "www", Name: "example", TLD: "co.uk"}, r)
+
+	o = newOptions([]Option{WithDownloader(dl), WithMirrors(GitHubMirror)})
+	err = o.validate("/tmp/tld.cache")
+	if assert.IsType(t, &OptionsError{}, err) {
+		assert.Equal(t, []string{"a downloader cannot be combined with a list URL, mirrors, a proxy or a transport"}, err.(*OptionsError).Problems)
+	}
+}
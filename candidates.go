@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"strings"
+)
+
+// GenOption configures GenerateCandidates
+type GenOption func(*genOptions)
+
+type genOptions struct {
+	levels bool
+}
+
+// WithEveryLevel combines every word with each level of the apex down to
+// its registrable domain, "www.dev.example.com" with the word "api" yields
+// "api.www.dev.example.com", "api.dev.example.com" and "api.example.com"
+func WithEveryLevel() GenOption {
+	return func(o *genOptions) {
+		o.levels = true
+	}
+}
+
+// GenerateCandidates yields a host name for every word of wordlist prefixed
+// to the registrable domain of apex, for brute forcing subdomains. The
+// wordlist has one word per line, blank lines and lines starting with "#"
+// are skipped and a word may hold several labels ("dev.api"). Words with
+// labels that are not letters, digits, hyphens and underscores, start or end
+// with a hyphen, or make the name exceed the DNS length limits are skipped,
+// as are words repeated in the list. Candidates are yielded lowercased in
+// their ASCII form.
+//
+// Nothing is yielded if apex cannot be parsed, and yielding stops at the
+// first error reading wordlist.
+func (d *Domain) GenerateCandidates(apex string, wordlist io.Reader, opts ...GenOption) iter.Seq[string] {
+	var o genOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return func(yield func(string) bool) {
+		rec, err := d.Parse(apex)
+		if err != nil {
+			return
+		}
+		bases := []string{rec.Name + "." + rec.TLD}
+		if o.levels {
+			bases, _ = d.LevelsE(rec.Hostname())
+		}
+		seen := make(map[string]struct{})
+		b := bufio.NewScanner(wordlist)
+		for b.Scan() {
+			word, ok := candidateWord(b.Text())
+			if !ok {
+				continue
+			}
+			if _, dup := seen[word]; dup {
+				continue
+			}
+			seen[word] = struct{}{}
+			for _, base := range bases {
+				host, err := toASCII(word + "." + base)
+				if err != nil || checkLengths(host) != nil {
+					continue
+				}
+				if !yield(host) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// candidateWord normalises a wordlist line and reports whether it is a word
+// made of valid labels
+func candidateWord(line string) (string, bool) {
+	word := strings.ToLower(strings.Trim(strings.TrimSpace(line), "."))
+	if word == "" || strings.HasPrefix(word, "#") {
+		return "", false
+	}
+	ascii, err := toASCII(word)
+	if err != nil {
+		return "", false
+	}
+	for _, label := range strings.Split(ascii, ".") {
+		if label == "" || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return "", false
+			}
+		}
+	}
+	return ascii, true
+}
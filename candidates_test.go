@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCandidates(t *testing.T) {
+	ex := newTestDomain(t)
+	words := "www\n\n# comment\nAPI\ndev.api\n-bad\nbad label\nwww\n_dmarc\nbücher\n" + strings.Repeat("a", 64) + "\n"
+
+	got := slices.Collect(ex.GenerateCandidates("shop.Example.co.uk", strings.NewReader(words)))
+	assert.Equal(t, []string{
+		"www.example.co.uk",
+		"api.example.co.uk",
+		"dev.api.example.co.uk",
+		"_dmarc.example.co.uk",
+		"xn--bcher-kva.example.co.uk",
+	}, got)
+
+	got = slices.Collect(ex.GenerateCandidates("www.dev.example.com", strings.NewReader("api\n"), WithEveryLevel()))
+	assert.Equal(t, []string{"api.www.dev.example.com", "api.dev.example.com", "api.example.com"}, got)
+
+	assert.Empty(t, slices.Collect(ex.GenerateCandidates("naan.example", strings.NewReader(words))))
+
+	// stopping early must not read further words
+	for range ex.GenerateCandidates("example.com", strings.NewReader(words)) {
+		break
+	}
+}
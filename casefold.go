@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// CasePolicy selects how Parse folds the case of its input before matching
+// it against the suffix list. Folding never depends on a locale.
+type CasePolicy int
+
+const (
+	// CaseIDNA is the default: ASCII labels have only the letters A to Z
+	// folded, so no Unicode special case can turn an ASCII name into a
+	// different one, and labels with non-ASCII characters get the UTS #46
+	// mapping of golang.org/x/net/idna's Lookup profile: case folding,
+	// fullwidth letters and digits become ASCII, compatibility characters
+	// such as "ﬁ" their decomposition, and the result is in NFC.
+	CaseIDNA CasePolicy = iota
+	// CaseASCII folds only the letters A to Z and leaves every other
+	// character as given
+	CaseASCII
+	// CaseUnicode applies strings.ToLower to the whole input, as Parse did
+	// before case policies existed
+	CaseUnicode
+)
+
+// WithCasePolicy sets how Parse folds the case of its input, the default
+// is CaseIDNA. WithPreserveCase still restores the original case in the
+// record.
+func WithCasePolicy(p CasePolicy) Option {
+	return func(o *options) {
+		o.casePolicy = p
+	}
+}
+
+// foldCase lowercases s according to p
+func foldCase(s string, p CasePolicy) string {
+	switch p {
+	case CaseUnicode:
+		return strings.ToLower(s)
+	case CaseASCII:
+		return foldASCII(s)
+	}
+	if isASCII(s) {
+		return foldASCII(s)
+	}
+	labels := strings.Split(s, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			labels[i] = foldASCII(label)
+		} else {
+			labels[i] = mapIDNA(label)
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+// foldASCII lowercases the letters A to Z of s and nothing else
+func foldASCII(s string) string {
+	i := strings.IndexFunc(s, func(r rune) bool { return r >= 'A' && r <= 'Z' })
+	if i < 0 {
+		return s
+	}
+	b := []byte(s)
+	for ; i < len(b); i++ {
+		if b[i] >= 'A' && b[i] <= 'Z' {
+			b[i] += 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+// lookupMapping is the UTS #46 mapping of the Lookup profile of x/net/idna,
+// nontransitional so "ß" is kept, without the STD3 rules so "_" is too
+var lookupMapping = idna.New(idna.MapForLookup(), idna.Transitional(false), idna.StrictDomainName(false))
+
+// mapIDNA applies the UTS #46 mapping to a label. Validation errors are left
+// to the later checks of Parse, the mapped label is returned either way.
+func mapIDNA(label string) string {
+	mapped, _ := lookupMapping.ToUnicode(label)
+	return mapped
+}
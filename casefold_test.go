@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFoldCase(t *testing.T) {
+	tests := []struct {
+		i                  string
+		idna, ascii, plain string
+	}{
+		{i: "WWW.Example.COM", idna: "www.example.com", ascii: "www.example.com", plain: "www.example.com"},
+		{i: "İSTANBUL.Example.com", idna: "i̇stanbul.example.com", ascii: "İstanbul.example.com", plain: "istanbul.example.com"},
+		{i: "ＥＸＡＭＰＬＥ.COM", idna: "example.com", ascii: "ＥＸＡＭＰＬＥ.com", plain: "ｅｘａｍｐｌｅ.com"},
+		{i: "BÜCHER.DE", idna: "bücher.de", ascii: "bÜcher.de", plain: "bücher.de"},
+		{i: "ﬁsh.Example.com", idna: "fish.example.com", ascii: "ﬁsh.example.com", plain: "ﬁsh.example.com"},
+		{i: "\u2126MEGA.com", idna: "ωmega.com", ascii: "\u2126mega.com", plain: "ωmega.com"},
+		{i: "STRAßE.de", idna: "straße.de", ascii: "straße.de", plain: "straße.de"},
+	}
+	// strings.ToLower turns the IDN "İSTANBUL" into the ASCII name "istanbul"
+	for _, ts := range tests {
+		assert.Equal(t, ts.idna, foldCase(ts.i, CaseIDNA), ts.i)
+		assert.Equal(t, ts.ascii, foldCase(ts.i, CaseASCII), ts.i)
+		assert.Equal(t, ts.plain, foldCase(ts.i, CaseUnicode), ts.i)
+	}
+
+	ex := newTestDomain(t)
+	r, err := ex.Parse("ＷＷＷ.Example.ＣＯＭ")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "www", Name: "example", TLD: "com"}, r)
+	r, err = ex.Parse("ﬁsh.com")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Name: "fish", TLD: "com"}, r)
+	ascii := newTestDomain(t, WithCasePolicy(CaseASCII))
+	r, err = ascii.Parse("BÜCHER.DE")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Name: "bÜcher", TLD: "de"}, r)
+}
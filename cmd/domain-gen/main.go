@@ -0,0 +1,62 @@
+// Command domain-gen compiles a suffix list snapshot into a Go source file
+// for domain.NewFromCompiled, pinning the list at build time. It is meant to
+// be run from a go:generate directive next to a vendored copy of the list:
+//
+//	//go:generate go run github.com/lynxsecurity/domain/cmd/domain-gen -list public_suffix_list.dat -o suffixlist.go
+//
+// Usage:
+//
+//	domain-gen -list file [-o file] [-pkg name] [-var name]
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/lynxsecurity/domain"
+)
+
+func main() {
+	list := flag.String("list", "", "suffix list or cache file to compile, - for standard input")
+	out := flag.String("o", "", "file to write, standard output if empty")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package of the generated file, defaults to the go:generate package")
+	name := flag.String("var", "SuffixList", "name of the generated variable")
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("domain-gen: ")
+
+	if *list == "" {
+		log.Fatal("-list is required")
+	}
+	if *pkg == "" {
+		*pkg = "main"
+	}
+	var in io.Reader = os.Stdin
+	if *list != "-" {
+		f, err := os.Open(*list)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+	compiled, err := domain.Compile(in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var src bytes.Buffer
+	if err := compiled.WriteGo(&src, *pkg, *name); err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		_, err = src.WriteTo(os.Stdout)
+	} else {
+		err = os.WriteFile(*out, src.Bytes(), 0644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,52 @@
+// Command domaind serves the domain parser as a JSON API, see package server
+// for the endpoints.
+//
+// Usage:
+//
+//	domaind [-addr :8080] [-cache /tmp/tld.cache] [-refresh 24h] [-max-age 72h]
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/lynxsecurity/domain"
+	"github.com/lynxsecurity/domain/server"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	cache := flag.String("cache", "/tmp/tld.cache", "suffix list cache file")
+	refresh := flag.Duration("refresh", 24*time.Hour, "how often to download a new suffix list, 0 to never")
+	maxAge := flag.Duration("max-age", 0, "age of the suffix list at which /readyz fails, 0 for no limit")
+	flag.Parse()
+
+	if err := run(*addr, *cache, *refresh, *maxAge); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run serves until the listener fails, stopping the background refresh on
+// the way out
+func run(addr, cache string, refresh, maxAge time.Duration) error {
+	d, err := domain.New(cache)
+	if err != nil {
+		return err
+	}
+	if refresh > 0 {
+		stop := d.StartAutoRefresh(refresh, func(r domain.UpdateReport) {
+			switch {
+			case r.Err != nil:
+				log.Printf("refresh failed: %v", r.Err)
+			case r.Changed():
+				log.Printf("refreshed suffix list to %s: %d rules added, %d removed", r.Current, len(r.Added), len(r.Removed))
+			}
+		})
+		defer stop()
+	}
+
+	log.Printf("listening on %s with suffix list %s", addr, d.ListVersion())
+	return http.ListenAndServe(addr, server.New(d, server.WithMaxListAge(maxAge)))
+}
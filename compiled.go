@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CompiledList is a suffix list compiled ahead of time into Go source by
+// cmd/domain-gen. A Domain created from one with NewFromCompiled does no I/O
+// at all and parses with exactly the list pinned at build time, which makes
+// builds reproducible.
+type CompiledList struct {
+	// Version, Commit and Downloaded are the ListVersion of the snapshot,
+	// Downloaded in RFC 3339 format or empty when unknown
+	Version    string
+	Commit     string
+	Downloaded string
+	// Sections holds the rules in list order, grouped by section
+	Sections []CompiledSection
+	// Data and Ends are the sorted lookup table of every rule and its
+	// alternate Unicode or punycode form, packed like WithCompactStorage
+	// packs them: rule i spans Data[Ends[i-1]:Ends[i]]
+	Data string
+	Ends []uint32
+}
+
+// CompiledSection is a run of consecutive rules from one section of the list
+type CompiledSection struct {
+	Section Section
+	Rules   []string
+}
+
+// Compile reads a suffix list, in the publicsuffix.org format or a cache
+// file, and compiles it for WriteGo. The download time is only taken from a
+// cache file header, so compiling the same list twice gives the same result.
+func Compile(list io.Reader) (*CompiledList, error) {
+	var cache bytes.Buffer
+	if err := writeList(&cache, list, time.Time{}); err != nil {
+		return nil, fmt.Errorf("Could not read suffix list: %v", err)
+	}
+	rules, version := readRules(&cache)
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("Could not read suffix list: no rules found")
+	}
+	c := &CompiledList{Version: version.Version, Commit: version.Commit}
+	if !version.Downloaded.IsZero() {
+		c.Downloaded = version.Downloaded.Format(time.RFC3339)
+	}
+	for i, r := range rules {
+		if i == 0 || r.Section != rules[i-1].Section {
+			c.Sections = append(c.Sections, CompiledSection{Section: r.Section})
+		}
+		last := &c.Sections[len(c.Sections)-1]
+		last.Rules = append(last.Rules, r.String())
+	}
+	table := newCompactRules(withAlternateForms(ruleTexts(rules)))
+	c.Data, c.Ends = string(table.data), table.ends
+	return c, nil
+}
+
+// sectionNames are the identifiers WriteGo uses for each section
+var sectionNames = map[Section]string{
+	SectionUnknown: "domain.SectionUnknown",
+	SectionICANN:   "domain.SectionICANN",
+	SectionPrivate: "domain.SectionPrivate",
+}
+
+// WriteGo writes c as a gofmt'ed Go source file of package pkg declaring
+// the variable name, ready to be passed to NewFromCompiled
+func (c *CompiledList) WriteGo(w io.Writer, pkg, name string) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by domain-gen from suffix list %s. DO NOT EDIT.\n\n", c.version())
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/lynxsecurity/domain\"\n\n")
+	fmt.Fprintf(&b, "// %s is suffix list %s, see domain.NewFromCompiled\n", name, c.version())
+	fmt.Fprintf(&b, "var %s = &domain.CompiledList{\n", name)
+	fmt.Fprintf(&b, "Version: %q,\nCommit: %q,\nDownloaded: %q,\n", c.Version, c.Commit, c.Downloaded)
+	b.WriteString("Sections: []domain.CompiledSection{\n")
+	for _, s := range c.Sections {
+		fmt.Fprintf(&b, "{Section: %s, Rules: []string{\n", sectionNames[s.Section])
+		for _, rule := range s.Rules {
+			fmt.Fprintf(&b, "%q,\n", rule)
+		}
+		b.WriteString("}},\n")
+	}
+	b.WriteString("},\n")
+	b.WriteString("Data: ")
+	for start := 0; ; start += 64 {
+		if start+64 >= len(c.Data) {
+			fmt.Fprintf(&b, "%s,\n", strconv.Quote(c.Data[start:]))
+			break
+		}
+		fmt.Fprintf(&b, "%s +\n", strconv.Quote(c.Data[start:start+64]))
+	}
+	b.WriteString("Ends: []uint32{")
+	for i, end := range c.Ends {
+		if i%12 == 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%d, ", end)
+	}
+	b.WriteString("\n},\n}\n")
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("Could not format generated code: %v", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// version returns the ListVersion of the snapshot
+func (c *CompiledList) version() ListVersion {
+	v := ListVersion{Version: c.Version, Commit: c.Commit}
+	if t, err := time.Parse(time.RFC3339, c.Downloaded); err == nil {
+		v.Downloaded = t
+	}
+	return v
+}
+
+// check makes sure the lookup table is consistent before it is used
+func (c *CompiledList) check() error {
+	prev := uint32(0)
+	for _, end := range c.Ends {
+		if end < prev {
+			return fmt.Errorf("Could not read compiled list: lookup table is not sorted")
+		}
+		prev = end
+	}
+	if int(prev) != len(c.Data) {
+		return fmt.Errorf("Could not read compiled list: lookup table does not match its data")
+	}
+	if c.Downloaded != "" {
+		if _, err := time.Parse(time.RFC3339, c.Downloaded); err != nil {
+			return fmt.Errorf("Could not read compiled list: %v", err)
+		}
+	}
+	return nil
+}
+
+// store returns the lookup table of c, unpacked into a map unless compact
+func (c *CompiledList) store(compact bool) ruleStore {
+	table := &compactRules{data: []byte(c.Data), ends: append([]uint32(nil), c.Ends...)}
+	if compact {
+		return table
+	}
+	m := make(mapRules, table.len())
+	for i := range table.ends {
+		m.add(string(table.at(i)))
+	}
+	return m
+}
+
+// NewFromCompiled creates a Domain from a list compiled by cmd/domain-gen,
+// without a cache file, download or parsing of the list. Options apply as
+// for NewFromList.
+func NewFromCompiled(c *CompiledList, opts ...Option) (*Domain, error) {
+	o := newOptions(append(opts, WithInMemory()))
+	if err := o.validate(""); err != nil {
+		return nil, err
+	}
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	var rules []Rule
+	for _, s := range c.Sections {
+		for _, rule := range s.Rules {
+			rules = append(rules, parseRule(rule, s.Section, PublicSuffixSource))
+		}
+	}
+	d := &Domain{opts: o}
+	d.install(rules, c.version(), c.store(o.compact))
+	return d, nil
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompiledList(t *testing.T) {
+	list := "// VERSION: 2026-01-01\n// ===BEGIN ICANN DOMAINS===\ncom\nuk\nco.uk\nck\n*.ck\n!www.ck\n公司.cn\ncn\n// ===END ICANN DOMAINS===\n// ===BEGIN PRIVATE DOMAINS===\nblogspot.com\n// ===END PRIVATE DOMAINS===\n"
+	c, err := Compile(strings.NewReader(list))
+	assert.NoError(t, err)
+	again, _ := Compile(strings.NewReader(list))
+	assert.Equal(t, c, again)
+	assert.Equal(t, "2026-01-01", c.Version)
+	assert.Equal(t, []CompiledSection{
+		{Section: SectionICANN, Rules: []string{"com", "uk", "co.uk", "ck", "*.ck", "!www.ck", "公司.cn", "cn"}},
+		{Section: SectionPrivate, Rules: []string{"blogspot.com"}},
+	}, c.Sections)
+
+	want, _ := NewFromList(strings.NewReader(list))
+	for _, compact := range []bool{false, true} {
+		var opts []Option
+		if compact {
+			opts = append(opts, WithCompactStorage())
+		}
+		d, err := NewFromCompiled(c, opts...)
+		assert.NoError(t, err)
+		assert.Equal(t, want.Rules(), d.Rules())
+		assert.Equal(t, "version 2026-01-01", d.ListVersion().String())
+		for _, host := range []string{"www.example.co.uk", "a.b.ck", "www.ck", "shop.example.xn--55qx5d.cn", "shop.example.公司.cn", "x.blogspot.com"} {
+			r, err := d.Parse(host)
+			w, werr := want.Parse(host)
+			assert.Equal(t, werr, err, host)
+			assert.Equal(t, w, r, host)
+		}
+	}
+
+	_, err = NewFromCompiled(&CompiledList{Data: "com", Ends: []uint32{2}})
+	assert.Error(t, err)
+	_, err = Compile(strings.NewReader("// nothing here\n"))
+	assert.Error(t, err)
+}
+
+func TestCompiledListWriteGo(t *testing.T) {
+	c, err := Compile(strings.NewReader("// ===BEGIN ICANN DOMAINS===\ncom\n公司.cn\ncn\n// ===END ICANN DOMAINS===\n"))
+	assert.NoError(t, err)
+	var src bytes.Buffer
+	assert.NoError(t, c.WriteGo(&src, "suffixes", "List"))
+	assert.True(t, strings.HasPrefix(src.String(), "// Code generated by domain-gen from suffix list unknown version. DO NOT EDIT.\n"))
+	assert.Contains(t, src.String(), "var List = &domain.CompiledList{")
+	assert.Contains(t, src.String(), "Section: domain.SectionICANN")
+	_, err = parser.ParseFile(token.NewFileSet(), "list.go", src.Bytes(), 0)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// confusables maps characters to the Latin prototype they are commonly
+// mistaken for, a subset of the Unicode TR39 confusables data covering the
+// lookalikes seen in phishing domains
+var confusables = map[rune]string{
+	// Cyrillic
+	'а': "a", 'е': "e", 'ё': "e", 'һ': "h", 'і': "i", 'ї': "i", 'ј': "j",
+	'к': "k", 'ӏ': "l", 'о': "o", 'р': "p", 'с': "c", 'ѕ': "s", 'у': "y",
+	'х': "x", 'ԁ': "d", 'ԛ': "q", 'ԝ': "w", 'ь': "b", 'п': "n",
+	// Greek
+	'α': "a", 'β': "b", 'γ': "y", 'ε': "e", 'η': "n", 'ι': "i", 'κ': "k",
+	'ν': "v", 'ο': "o", 'ρ': "p", 'τ': "t", 'υ': "u", 'χ': "x", 'ω': "w",
+	'ϲ': "c", 'ϳ': "j",
+	// Latin lookalikes
+	'ı': "i", 'ɩ': "i", 'ɑ': "a", 'ɡ': "g", 'ǀ': "l", 'ℓ': "l", 'ⅼ': "l", 'ⅰ': "i",
+	'ⅿ': "rn", 'ʋ': "u", 'ɒ': "a", 'ꮃ': "w",
+	// digits and letters that read alike, plus sequences that render alike
+	'0': "o", '1': "l", '|': "l", 'm': "rn",
+}
+
+// Skeleton maps s to a form in which visually confusable strings are
+// identical, so "exаmple" with a Cyrillic "а", "examp1e" and "example" share
+// a skeleton. Punycode labels are decoded first.
+func Skeleton(s string) string {
+	if u, err := toUnicode(strings.ToLower(s)); err == nil {
+		s = u
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		// fullwidth ASCII variants, common in pasted input
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r = unicode.ToLower(r - 0xFEE0)
+		}
+		if proto, ok := confusables[r]; ok {
+			b.WriteString(proto)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// registrable returns the registrable domain of the record
+func (r *Record) registrable() string {
+	return strings.ToLower(r.Name + "." + r.TLD)
+}
+
+// Confusable reports whether a and b have different registrable domains that
+// look the same, such as "exаmple.com" spelled with a Cyrillic "а"
+func Confusable(a, b *Record) bool {
+	ra, rb := unicodeName(a.registrable()), unicodeName(b.registrable())
+	return ra != rb && Skeleton(ra) == Skeleton(rb)
+}
+
+// unicodeName decodes punycode labels, leaving the name unchanged on error
+func unicodeName(name string) string {
+	if u, err := toUnicode(name); err == nil {
+		return u
+	}
+	return name
+}
+
+// Lookalike is a brand that a record's name imitates
+type Lookalike struct {
+	Brand string
+	// Skeleton is the skeleton shared by the brand and the record's name
+	Skeleton string
+	// MixedScript is set when the record's name mixes scripts, such as
+	// Latin and Cyrillic letters, which legitimate names rarely do
+	MixedScript bool
+}
+
+// FindLookalikes returns the brands whose name the record imitates without
+// being that brand, comparing the skeleton of the record's Name (without
+// the suffix) to the skeleton of every brand
+func FindLookalikes(r *Record, brands []string) []Lookalike {
+	name := unicodeName(strings.ToLower(r.Name))
+	skeleton := Skeleton(name)
+	var found []Lookalike
+	for _, brand := range brands {
+		brand = strings.ToLower(brand)
+		if brand == name || Skeleton(brand) != skeleton {
+			continue
+		}
+		found = append(found, Lookalike{Brand: brand, Skeleton: skeleton, MixedScript: mixedScript(name)})
+	}
+	return found
+}
+
+// MixedScriptLabels returns the labels of the record that mix letters of
+// different scripts, decoding punycode labels first
+func (r *Record) MixedScriptLabels() []string {
+	var mixed []string
+	for label := range r.LabelsSeq() {
+		if mixedScript(unicodeName(label)) {
+			mixed = append(mixed, label)
+		}
+	}
+	return mixed
+}
+
+// scripts whose letters are compared by mixedScript, Han, Kana and Hangul
+// are grouped because Japanese and Korean names mix them legitimately
+var scripts = []struct {
+	group  string
+	tables []*unicode.RangeTable
+}{
+	{"latin", []*unicode.RangeTable{unicode.Latin}},
+	{"cyrillic", []*unicode.RangeTable{unicode.Cyrillic}},
+	{"greek", []*unicode.RangeTable{unicode.Greek}},
+	{"armenian", []*unicode.RangeTable{unicode.Armenian}},
+	{"hebrew", []*unicode.RangeTable{unicode.Hebrew}},
+	{"arabic", []*unicode.RangeTable{unicode.Arabic}},
+	{"cjk", []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul}},
+}
+
+// mixedScript reports whether s holds letters from more than one script
+func mixedScript(s string) bool {
+	seen := ""
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		for _, sc := range scripts {
+			if unicode.In(r, sc.tables...) {
+				if seen != "" && seen != sc.group {
+					return true
+				}
+				seen = sc.group
+				break
+			}
+		}
+	}
+	return false
+}
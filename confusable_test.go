@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{a: "example", b: "exаmple", same: true},
+		{a: "example", b: "examp1e", same: true},
+		{a: "paypal", b: "раураl", same: true},
+		{a: "google", b: "g00gle", same: true},
+		{a: "modern", b: "rnodern", same: true},
+		{a: "apple", b: "ａｐｐｌｅ", same: true},
+		{a: "example", b: "xn--exmple-4nf", same: true},
+		{a: "example", b: "exampel", same: false},
+	}
+	for _, ts := range tests {
+		assert.Equal(t, ts.same, Skeleton(ts.a) == Skeleton(ts.b), "%s %s", ts.a, ts.b)
+	}
+}
+
+func TestConfusable(t *testing.T) {
+	ex := newTestDomain(t)
+	a, _ := ex.Parse("login.example.com")
+	b, _ := ex.Parse("www.exаmple.com")
+	c, _ := ex.Parse("example.com")
+	assert.True(t, Confusable(a, b))
+	assert.False(t, Confusable(a, c))
+	assert.Equal(t, []string{"exаmple"}, b.MixedScriptLabels())
+	assert.Empty(t, c.MixedScriptLabels())
+
+	assert.Equal(t, []Lookalike{{Brand: "example", Skeleton: "exarnple", MixedScript: true}}, FindLookalikes(b, []string{"Example", "other"}))
+	assert.Empty(t, FindLookalikes(c, []string{"example"}))
+}
+
+func TestMixedScript(t *testing.T) {
+	assert.False(t, mixedScript("example-1"))
+	assert.False(t, mixedScript("пример"))
+	assert.False(t, mixedScript("日本語のドメイン"))
+	assert.True(t, mixedScript("pаypal"))
+	assert.True(t, mixedScript("αpple"))
+}
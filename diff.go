@@ -0,0 +1,119 @@
+package domain
+
+import "strings"
+
+// LabelChangeKind classifies one aligned label position of a LabelDiff
+type LabelChangeKind int
+
+const (
+	// LabelSame means both hosts have the same label at this position
+	LabelSame LabelChangeKind = iota
+	// LabelChanged means both hosts have a label here but they differ
+	LabelChanged
+	// LabelAdded means only the second host has a label here
+	LabelAdded
+	// LabelRemoved means only the first host has a label here
+	LabelRemoved
+)
+
+// String returns the name of the change kind
+func (k LabelChangeKind) String() string {
+	switch k {
+	case LabelSame:
+		return "same"
+	case LabelChanged:
+		return "changed"
+	case LabelAdded:
+		return "added"
+	case LabelRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// LabelChange is one label position compared by DiffHostsLabels
+type LabelChange struct {
+	// Position counts labels from the right, the last label of the TLD is 0
+	Position int
+	// A and B are the labels of each host, empty when the host has none here
+	A, B string
+	Kind LabelChangeKind
+}
+
+// LabelDiff describes how two hostnames differ label by label
+type LabelDiff struct {
+	// A and B are the parsed hosts, nil when a host could not be parsed, in
+	// which case its labels are compared as written
+	A, B *Record
+	// Labels holds one entry per position, ordered from the TLD side
+	Labels []LabelChange
+	// SameTLD is set when both hosts parsed with the same public suffix
+	SameTLD bool
+	// SameRegistrable is set when both hosts share a registrable domain
+	SameRegistrable bool
+}
+
+// Changed returns the positions that are not LabelSame
+func (l LabelDiff) Changed() []LabelChange {
+	var changed []LabelChange
+	for _, c := range l.Labels {
+		if c.Kind != LabelSame {
+			changed = append(changed, c)
+		}
+	}
+	return changed
+}
+
+// DiffHostsLabels compares two hostnames label by label, aligned from the TLD
+// side, to explain why similar looking hosts are or are not related
+func DiffHostsLabels(a, b string, d *Domain) LabelDiff {
+	var diff LabelDiff
+	var la, lb []string
+	diff.A, la = diffLabels(a, d)
+	diff.B, lb = diffLabels(b, d)
+	if diff.A != nil && diff.B != nil {
+		diff.SameTLD = diff.A.TLD == diff.B.TLD
+		diff.SameRegistrable = diff.SameTLD && diff.A.Name == diff.B.Name
+	}
+
+	n := len(la)
+	if len(lb) > n {
+		n = len(lb)
+	}
+	for pos := 0; pos < n; pos++ {
+		c := LabelChange{Position: pos}
+		if pos < len(la) {
+			c.A = la[len(la)-1-pos]
+		}
+		if pos < len(lb) {
+			c.B = lb[len(lb)-1-pos]
+		}
+		switch {
+		case pos >= len(la):
+			c.Kind = LabelAdded
+		case pos >= len(lb):
+			c.Kind = LabelRemoved
+		case c.A == c.B:
+			c.Kind = LabelSame
+		default:
+			c.Kind = LabelChanged
+		}
+		diff.Labels = append(diff.Labels, c)
+	}
+	return diff
+}
+
+// diffLabels parses host and returns its labels, falling back to splitting
+// the lowercased input when it cannot be parsed
+func diffLabels(host string, d *Domain) (*Record, []string) {
+	rec, err := d.Parse(host)
+	if err != nil {
+		return nil, strings.Split(strings.ToLower(strings.Trim(host, ".")), ".")
+	}
+	var labels []string
+	if rec.Subdomain != "" {
+		labels = strings.Split(rec.Subdomain, ".")
+	}
+	labels = append(labels, rec.Name)
+	return rec, append(labels, strings.Split(rec.TLD, ".")...)
+}
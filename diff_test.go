@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffHostsLabels(t *testing.T) {
+	ex := newTestDomain(t)
+
+	diff := DiffHostsLabels("api.example.co.uk", "www.dev.example.co.uk", ex)
+	assert.True(t, diff.SameTLD)
+	assert.True(t, diff.SameRegistrable)
+	assert.Equal(t, []LabelChange{
+		{Position: 0, A: "uk", B: "uk", Kind: LabelSame},
+		{Position: 1, A: "co", B: "co", Kind: LabelSame},
+		{Position: 2, A: "example", B: "example", Kind: LabelSame},
+		{Position: 3, A: "api", B: "dev", Kind: LabelChanged},
+		{Position: 4, A: "", B: "www", Kind: LabelAdded},
+	}, diff.Labels)
+
+	diff = DiffHostsLabels("login.example.com", "login.examp1e.com", ex)
+	assert.True(t, diff.SameTLD)
+	assert.False(t, diff.SameRegistrable)
+	assert.Equal(t, []LabelChange{
+		{Position: 1, A: "example", B: "examp1e", Kind: LabelChanged},
+	}, diff.Changed())
+
+	diff = DiffHostsLabels("a.b.example.com", "example.nonexist", ex)
+	assert.Nil(t, diff.B)
+	assert.False(t, diff.SameTLD)
+	assert.Equal(t, []LabelChange{
+		{Position: 0, A: "com", B: "nonexist", Kind: LabelChanged},
+		{Position: 2, A: "b", B: "", Kind: LabelRemoved},
+		{Position: 3, A: "a", B: "", Kind: LabelRemoved},
+	}, diff.Changed())
+}
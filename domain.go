@@ -41,194 +41,626 @@ package domain
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
-	"log"
-	"net/http"
+	"io"
+	"io/fs"
+	"net"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 )
 
 // Domain is the core structure, a domain name parser
 type Domain struct {
-	tlds  *tldMap
+	// state holds everything loaded from the suffix list, see ruleSnapshot
+	state atomic.Pointer[ruleSnapshot]
+	opts  options
+
+	// mu serializes writers of state and guards the refresh stats
+	mu sync.Mutex
+
+	stats   domainStats
+	results *resultCache
+
+	listenersMu sync.Mutex
+	listeners   []*rulesListener
+
+	stopReload func()
+	closeOnce  sync.Once
+
 	Cache string
 }
 
 // Record holds a parsed domain name
 type Record struct {
 	Subdomain, Name, TLD string
+	// Port is the port of a "host:port" input, empty when none was given
+	Port string
+	// ServiceLabels holds leading underscore labels such as "_dmarc" or
+	// "_443._tcp", split off the Subdomain when WithServiceLabels is set
+	ServiceLabels []string
+	// IP is the address a reverse DNS name points to, set when
+	// WithReverseDNS is used, nil for other names
+	IP net.IP
+	// SpecialUse is the special-use domain, such as "onion" or "local", the
+	// name is under, empty for ordinary names. See WithSpecialUse.
+	SpecialUse string
+	// Provider is the hosting service, such as "Amazon S3", whose suffix the
+	// name is under, set when WithHostingProviders is used
+	Provider string
+	// AltRoot is the name of the RulePack, such as "ens", whose suffix the
+	// name is under, empty for names under the ICANN root
+	AltRoot string
+	// Wildcard is set for inputs with a leading "*" label, such as the
+	// certificate name "*.example.co.uk", the "*" is not part of Subdomain
+	Wildcard bool
+	// Warnings holds non-fatal problems found while parsing, such as a stale
+	// suffix list reported by WithStaleWarning
+	Warnings []string
+}
+
+// host joins the non-empty parts of the record into a hostname
+func (r *Record) host() string {
+	h := r.Name + "." + r.TLD
+	if r.Subdomain != "" {
+		h = r.Subdomain + "." + h
+	}
+	return strings.ToLower(r.servicePrefix() + h)
+}
+
+// servicePrefix returns the wildcard marker and service labels followed by a
+// dot, or ""
+func (r *Record) servicePrefix() string {
+	prefix := ""
+	if r.Wildcard {
+		prefix = "*."
+	}
+	if len(r.ServiceLabels) == 0 {
+		return prefix
+	}
+	return prefix + strings.Join(r.ServiceLabels, ".") + "."
 }
 
 // String() converts a record to a string
 func (r *Record) String() string {
-	return strings.ToLower(fmt.Sprintf("%s.%s.%s", r.Subdomain, r.Name, r.TLD))
+	prefix, sub := r.servicePrefix(), r.Subdomain
+	if sub == "" && prefix != "" {
+		prefix, sub = "", strings.TrimSuffix(prefix, ".")
+	}
+	return strings.ToLower(fmt.Sprintf("%s%s.%s.%s", prefix, sub, r.Name, r.TLD))
 }
 
 // New creates and returns a new domain object
-func New(cacheFile string) (*Domain, error) {
-	if !cacheExists(cacheFile) {
-		err := newCache(cacheFile)
-		if err != nil {
+func New(cacheFile string, opts ...Option) (*Domain, error) {
+	o := newOptions(opts)
+	if err := o.validate(cacheFile); err != nil {
+		return nil, err
+	}
+	var rules []Rule
+	var version ListVersion
+	var list []byte
+	var cacheInfo os.FileInfo
+	var err error
+	switch {
+	case o.provider != nil:
+		rules, version, err = providerRules(&o)
+	case o.backend != nil:
+		list, err = loadBackend(&o)
+	case o.inMemory:
+		var buf bytes.Buffer
+		err = downloadList(&buf, &o)
+		list = buf.Bytes()
+	default:
+		if err = ensureCache(cacheFile, &o); err == nil {
+			cacheInfo, _ = os.Stat(cacheFile)
+			list, err = readCache(cacheFile)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if o.provider == nil {
+		rules, version = readRules(bytes.NewReader(list))
+	}
+	d := &Domain{Cache: cacheFile, opts: o}
+	d.install(rules, version, nil)
+	d.recordCacheFile(cacheInfo)
+	if o.rootZone {
+		if err := d.loadRootZone(&o); err != nil {
 			return nil, err
 		}
 	}
+	if o.autoReload {
+		d.stopReload = d.autoReload(o.onReloadError)
+	}
+	return d, nil
+}
+
+// NewFromList creates a Domain from a suffix list in the publicsuffix.org
+// format read from list, without a cache file or download. It suits
+// platforms without a file system such as js/wasm, and tests.
+func NewFromList(list io.Reader, opts ...Option) (*Domain, error) {
+	return newFromList(list, time.Now(), opts)
+}
 
-	cache, err := os.Open(cacheFile)
+// NewFromFS creates a Domain from the suffix list or cache file name in fsys,
+// such as a list vendored into the binary with embed, without writing it to
+// disk. The list's age is taken from its DOWNLOADED header or else from the
+// file's modification time, which embedded files do not have.
+func NewFromFS(fsys fs.FS, name string, opts ...Option) (*Domain, error) {
+	f, err := fsys.Open(name)
 	if err != nil {
 		return nil, fmt.Errorf("Could not open cache file: %v", err)
 	}
-	defer cache.Close()
-	tlds := &tldMap{m: make(map[string]struct{})}
-	b := bufio.NewScanner(cache)
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("Could not open cache file: %v", err)
+	}
+	return newFromList(f, info.ModTime(), opts)
+}
+
+// newFromList creates an in-memory Domain from a list downloaded at the given
+// time
+func newFromList(list io.Reader, downloaded time.Time, opts []Option) (*Domain, error) {
+	o := newOptions(append(opts, WithInMemory()))
+	if err := o.validate(""); err != nil {
+		return nil, err
+	}
+	var cache bytes.Buffer
+	if err := writeList(&cache, list, downloaded); err != nil {
+		return nil, fmt.Errorf("Could not read suffix list: %v", err)
+	}
+	return newDomain("", &cache, &o), nil
+}
+
+// newDomain builds a Domain from a suffix list, merging any extra sources
+func newDomain(cacheFile string, list io.Reader, o *options) *Domain {
+	d := &Domain{Cache: cacheFile, opts: *o}
+	d.load(list)
+	return d
+}
+
+// load replaces the rules of d with the ones read from list
+func (d *Domain) load(list io.Reader) {
+	rules, version := readRules(list)
+	d.install(rules, version, nil)
+}
+
+// install replaces the rules of d with rules. store is the lookup table built
+// from them, or nil to build it here; it is rebuilt anyway when extra sources
+// have to be merged in.
+func (d *Domain) install(rules []Rule, version ListVersion, store ruleStore) {
+	texts := ruleTexts(rules)
+	var resolutions map[string]Resolution
+	if len(d.opts.sources) > 0 {
+		base := make(map[string]Rule, len(rules))
+		for _, r := range rules {
+			base[r.String()] = r
+		}
+		sources := append([]Source{{Name: PublicSuffixSource, Rules: texts}}, d.opts.sources...)
+		texts, resolutions = mergeSources(sources)
+		creditBase(resolutions, base)
+		rules = make([]Rule, len(texts))
+		for i, text := range texts {
+			rules[i] = parseRule(text, base[text].Section, resolutions[text].Source)
+		}
+	} else {
+		resolutions = ruleResolutions(rules)
+	}
+	if store == nil || len(d.opts.sources) > 0 {
+		store = newRuleStore(withAlternateForms(texts), d.opts.compact)
+	}
+	var private []string
+	for _, r := range rules {
+		if r.Section == SectionPrivate {
+			private = append(private, r.String())
+		}
+	}
+	privateSet := make(map[string]struct{}, len(private))
+	for _, rule := range withAlternateForms(private) {
+		privateSet[rule] = struct{}{}
+	}
+
+	listTime, listTimeErr := d.listTime(version)
+
+	d.mu.Lock()
+	previous := d.snapshot()
+	d.publish(func(s *ruleSnapshot) {
+		s.store, s.rules, s.resolutions, s.private, s.version = store, rules, resolutions, privateSet, version
+		s.listTime, s.listTimeErr = listTime, listTimeErr
+	})
+	d.mu.Unlock()
+	initial := previous == nil
+	if d.results != nil {
+		d.results.purge()
+	} else if initial && d.opts.resultCache > 0 {
+		d.results = newResultCache(d.opts.resultCache)
+	}
+	if !initial {
+		d.notifyRulesChanged(previous.rules, previous.version, rules, version)
+	}
+}
+
+// readRules reads one suffix rule per line along with the list version and
+// section markers recorded in the cache
+func readRules(r io.Reader) ([]Rule, ListVersion) {
+	var rules []Rule
+	var version ListVersion
+	var section Section
+	b := bufio.NewScanner(r)
 	for b.Scan() {
-		tlds.add(b.Text())
+		line := b.Text()
+		if strings.HasPrefix(line, "//") {
+			if s, ok := sectionMarker(line); ok {
+				section = s
+			} else {
+				version.parseHeader(line)
+			}
+			continue
+		}
+		if line != "" {
+			rules = append(rules, parseRule(line, section, PublicSuffixSource))
+		}
 	}
-	d := &Domain{
-		Cache: cacheFile,
-		tlds:  tlds,
+	return rules, version
+}
+
+// withAlternateForms adds the punycode form of every Unicode rule and the
+// Unicode form of every punycode rule, so a name matches whichever way the
+// caller spells it
+func withAlternateForms(rules []string) []string {
+	out := rules
+	for _, rule := range rules {
+		bare := strings.TrimPrefix(rule, "!")
+		prefix := rule[:len(rule)-len(bare)]
+		alt, err := toASCII(bare)
+		if err == nil && alt == bare {
+			alt, err = toUnicode(bare)
+		}
+		if err == nil && alt != bare {
+			out = append(out, prefix+alt)
+		}
 	}
-	return d, nil
+	return out
 }
 
-// Parse parses a domain and extracts it into a Record object
-func (d *Domain) Parse(domain string) (*Record, error) {
+// newRuleStore creates a store holding rules, packed into a compactRules
+// store when compact is set
+func newRuleStore(rules []string, compact bool) ruleStore {
+	if compact {
+		return newCompactRules(rules)
+	}
+	m := make(mapRules, len(rules))
+	for _, rule := range rules {
+		m.add(rule)
+	}
+	return m
+}
+
+// Parse parses a domain and extracts it into a Record object. A "host:port"
+// input records the port in Record.Port, IP literals fail with
+// ErrCodeIPAddress even with a port. Options change the rules for this call
+// only, see ParseOption.
+func (d *Domain) Parse(domain string, opts ...ParseOption) (*Record, error) {
+	if d.results == nil || len(opts) > 0 {
+		rec, err := d.parse(domain, newParseOptions(opts))
+		d.countParse(err)
+		if err == nil {
+			d.addWarnings(rec)
+		}
+		return rec, err
+	}
+	key := d.resultKey(domain)
+	rec, gen, ok := d.results.get(key)
+	if ok {
+		d.stats.resultHits.Add(1)
+		d.countParse(nil)
+		d.addWarnings(rec)
+		return rec, nil
+	}
+	d.stats.resultMisses.Add(1)
+	rec, err := d.parse(domain, nil)
+	d.countParse(err)
+	if err == nil {
+		// cached without the warnings of the list's state, which change
+		// while the record stays cached
+		d.results.put(key, rec, gen)
+		d.addWarnings(rec)
+	}
+	return rec, err
+}
+
+// parse implements Parse without the warnings of addWarnings, po may be nil
+func (d *Domain) parse(domain string, po *parseOptions) (*Record, error) {
 	var rec Record
 	var err error
-	domain = strings.ToLower(domain)
+	if err := d.checkInputLength(domain); err != nil {
+		return nil, err
+	}
+	if d.opts.lenient {
+		domain = lenientHost(domain)
+	}
+	domain = normalizeDots(domain)
+	if err := d.checkLabelCount(domain); err != nil {
+		return nil, err
+	}
+	hidden := invisible
+	if d.opts.idnaProfile == IDNARegistration {
+		// joiners are left to the CONTEXTJ rules of the registration check
+		hidden = invisibleNonJoiner
+	}
+	if i := strings.IndexFunc(domain, hidden); i >= 0 {
+		if d.opts.rejectInvisible {
+			r, _ := utf8.DecodeRuneInString(domain[i:])
+			return nil, newParseError(domain, ErrCodeInvisibleCharacter, i, fmt.Sprintf("domain name cannot contain invisible character %U", r), nil)
+		}
+		domain = stripFunc(domain, hidden)
+	}
+	domain, rec.Port, err = splitPort(domain)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(domain, "*.") {
+		rec.Wildcard = true
+		domain = domain[2:]
+	}
+	original := domain
+	domain = foldCase(domain, d.opts.casePolicy)
 	err = validator(domain)
 	if err != nil {
 		return nil, err
 	}
-	chunks := strings.Split(domain, ".")
-	cl := len(chunks)
-
-	var tld string
-	for i := cl - 1; i >= 0; i-- {
-		c := chunks[i]
-		if tld == "" {
-			tld = c
-		} else {
-			tld = c + "." + tld
+	labels := SplitLabels(domain)
+	offset := 0
+	for _, label := range labels {
+		if label == "" {
+			return nil, newParseError(domain, ErrCodeEmptyLabel, offset, "domain name cannot contain an empty label", nil)
+		}
+		offset += len(label) + 1
+	}
+	if d.opts.idnaProfile == IDNARegistration {
+		// names are registered as given, so only ASCII case is folded and
+		// characters the UTS #46 mapping would change fail the check
+		given := foldASCII(original)
+		givenLabels := SplitLabels(given)
+		if i, err := checkRegistration(givenLabels); err != nil {
+			return nil, newParseError(given, ErrCodeIDNA, labelOffset(givenLabels, i), err.Error(), nil)
 		}
-		if ok := d.tlds.exists(tld); ok {
-			rec.TLD = tld
-		} else if rec.Name == "" {
-			rec.Name = c
+	}
+	if d.opts.reverseDNS && parseReverse(&rec, labels) {
+		return &rec, nil
+	}
+	special, start, ok, err := d.specialUseStart(domain, labels)
+	if err != nil {
+		return nil, err
+	}
+	var rule string
+	if !ok {
+		start, rule, ok = d.matchRule(labels, po)
+		if ok {
+			d.stats.suffixHits.Add(1)
 		} else {
-			if rec.Subdomain == "" {
-				rec.Subdomain = c
-			} else {
-				rec.Subdomain = c + "." + rec.Subdomain
-			}
+			d.stats.suffixMisses.Add(1)
 		}
 	}
-	if rec.TLD == "" {
-		return nil, fmt.Errorf("parse: \"%s\": top level domain doe not exist", domain)
+	if !ok && d.opts.fallbackTLD {
+		start, ok = len(labels)-1, true
+		rec.Warnings = append(rec.Warnings, fmt.Sprintf("top level domain \"%s\" is not in the suffix list", labels[start]))
+	}
+	if !ok {
+		return nil, d.unknownTLDError(domain)
+	}
+	if start == 0 {
+		return nil, newInputError(domain, ErrCodeMissingName, "missing domain name")
+	}
+	rec.TLD = strings.Join(labels[start:], ".")
+	rec.Name = labels[start-1]
+	rec.Subdomain = strings.Join(labels[:start-1], ".")
+	if d.opts.preserveCase {
+		rec.restoreCase(SplitLabels(original))
 	}
-	if rec.Name == "" {
-		return nil, fmt.Errorf("parse: \"%s\": missing domain name", domain)
+	if d.opts.serviceLabels {
+		rec.ServiceLabels, rec.Subdomain = splitServiceLabels(rec.Subdomain)
 	}
+	rec.SpecialUse = special
+	rec.Provider = d.hostingProvider(labels)
+	rec.AltRoot = d.altRoot(rule)
 	return &rec, nil
 }
 
-// Levels returns all subdomain levels for a given record
+// suffixStart finds the public suffix of a name split into labels and returns
+// the index of its first label, following the prevailing rule algorithm of
+// https://publicsuffix.org/list/: every suffix of the name is checked against
+// exact ("a.b"), wildcard ("*.b") and exception ("!a.b") rules, an exception
+// wins over any other match and otherwise the longest match wins. An
+// exception's public suffix is the rule without its leftmost label. It
+// returns false if no rule matches.
+func (d *Domain) suffixStart(labels []string) (int, bool) {
+	start, _, found := d.matchRule(labels, nil)
+	return start, found
+}
+
+// matchRule is suffixStart that also returns the text of the prevailing
+// rule, with the extra and skipped rules of po, which may be nil
+func (d *Domain) matchRule(labels []string, po *parseOptions) (start int, rule string, found bool) {
+	rules := d.snapshot().store
+	exists := rules.exists
+	if po != nil {
+		exists = func(rule string) bool {
+			if po.extra.exists(rule) {
+				return true
+			}
+			return rules.exists(rule) && !(po.withoutPrivate && d.isPrivate(rule))
+		}
+	}
+	for i := range labels {
+		candidate := strings.Join(labels[i:], ".")
+		if exists("!" + candidate) {
+			return i + 1, "!" + candidate, true
+		}
+		if found {
+			continue
+		}
+		if exists(candidate) {
+			start, rule, found = i, candidate, true
+		} else if wildcard := "*." + strings.Join(labels[i+1:], "."); i+1 < len(labels) && exists(wildcard) {
+			start, rule, found = i, wildcard, true
+		}
+	}
+	return start, rule, found
+}
+
+// restoreCase replaces the lowercased parts of the record with the same
+// labels taken from the original input
+func (r *Record) restoreCase(labels []string) {
+	tld := strings.Count(r.TLD, ".") + 1
+	if len(labels) < tld+1 {
+		return
+	}
+	r.TLD = strings.Join(labels[len(labels)-tld:], ".")
+	r.Name = labels[len(labels)-tld-1]
+	if r.Subdomain != "" {
+		r.Subdomain = strings.Join(labels[:len(labels)-tld-1], ".")
+	}
+}
+
+// Levels returns all subdomain levels for a given record, or an empty slice if
+// the name cannot be parsed.
+//
+// Levels is kept for compatibility as a thin wrapper around LevelsE, new code
+// should call LevelsE so that unparseable input is not silently dropped.
+//
+// Deprecated: use LevelsE, or Levels of github.com/lynxsecurity/domain/v2,
+// which return the parse error.
 func (d *Domain) Levels(DomainName string) []string {
-	DomainName = strings.ToLower(DomainName)
-	var levels []string
-	h, err := d.Parse(DomainName)
+	levels, err := d.LevelsE(DomainName)
 	if err != nil {
 		return []string{}
 	}
-	t := len(DomainName) - len(h.TLD)
-	all := strings.Split(DomainName[:t], ".")
-	for i := 0; i <= len(all)-2; i++ {
-		sub := strings.Join(all[i:], ".")
-		sub += h.TLD
-		levels = append(levels, sub)
-	}
 	return levels
 }
 
-// newCache downloads the TLD suffix list and creates a new cache file
-func newCache(cacheFile string) error {
-	cachefp, err := os.Create(cacheFile)
+// LevelsE returns all subdomain levels for a given record along with any
+// error encountered while parsing it, so an unparseable name can be told
+// apart from an apex domain, whose only level is itself.
+func (d *Domain) LevelsE(DomainName string) ([]string, error) {
+	var levels []string
+	h, err := d.Parse(DomainName)
 	if err != nil {
-		return fmt.Errorf("Could not create new cache file: %v", err)
+		return nil, err
 	}
-	buf := bufio.NewWriter(cachefp)
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Get("https://publicsuffix.org/list/public_suffix_list.dat")
-	if err != nil {
-		return fmt.Errorf("Could not download suffix list: %v", err)
-	}
-	defer resp.Body.Close()
-	scan := bufio.NewScanner(resp.Body)
-	for scan.Scan() {
-		line := scan.Text()
-		if line != "" && !strings.HasPrefix(line, "/") {
-			_, err := buf.WriteString(line)
-			if err != nil {
-				log.Fatal(err)
-			}
-			_, err = buf.WriteString("\n")
-			if err != nil {
-				log.Fatal(err)
-			}
-
-		}
+	labels := []string{h.Name}
+	if h.Subdomain != "" {
+		labels = append(strings.Split(h.Subdomain, "."), h.Name)
 	}
-	buf.Flush()
-	cachefp.Close()
-	return nil
+	for i := range labels {
+		levels = append(levels, strings.Join(labels[i:], ".")+"."+h.TLD)
+	}
+	return levels, nil
 }
 
-// cacheExists checks if a file exists
-func cacheExists(cacheFile string) bool {
-	if _, err := os.Stat(cacheFile); err == nil {
-		return true
-	} else if os.IsNotExist(err) {
-		return false
-	}
-	return false
+// ruleSnapshot is everything loaded from a suffix list. It is never modified
+// once published: Parse reads it without locking, writers build a new one and
+// swap it in whole.
+type ruleSnapshot struct {
+	store       ruleStore
+	rules       []Rule
+	resolutions map[string]Resolution
+	private     map[string]struct{}
+	version     ListVersion
+	rootZone    map[string]struct{}
+
+	// listTime is when the list was downloaded, or listTimeErr why that is
+	// unknown, see CacheAge
+	listTime    time.Time
+	listTimeErr error
+
+	// cacheFile is the cache file the list was read from or written to, nil
+	// when unknown, see recordCacheFile
+	cacheFile os.FileInfo
 }
 
-// tldMap is a thread safe map structure
-type tldMap struct {
-	sync.RWMutex
-	m map[string]struct{}
+// snapshot returns the loaded state, a name looked up several times should
+// use one snapshot so that all lookups see the same rules
+func (d *Domain) snapshot() *ruleSnapshot {
+	return d.state.Load()
 }
 
-// exists checks if a tld exists
-func (t *tldMap) exists(tld string) bool {
-	t.RLock()
-	defer t.RUnlock()
-	_, ok := t.m[tld]
-	return ok
+// publish swaps in a copy of the loaded state changed by update, d.mu must
+// be held
+func (d *Domain) publish(update func(s *ruleSnapshot)) {
+	var s ruleSnapshot
+	if current := d.state.Load(); current != nil {
+		s = *current
+	}
+	update(&s)
+	d.state.Store(&s)
+}
+
+// splitServiceLabels splits leading underscore labels off a subdomain
+func splitServiceLabels(subdomain string) ([]string, string) {
+	var service []string
+	for strings.HasPrefix(subdomain, "_") {
+		i := strings.IndexByte(subdomain, '.')
+		if i < 0 {
+			return append(service, subdomain), ""
+		}
+		service = append(service, subdomain[:i])
+		subdomain = subdomain[i+1:]
+	}
+	return service, subdomain
 }
 
-// add adds a tld to the map
-func (t *tldMap) add(tld string) {
-	t.Lock()
-	defer t.Unlock()
-	t.m[tld] = struct{}{}
+// splitPort separates the port from "host:port" and "[host]:port" inputs.
+// The port must be decimal digits no larger than 65535. IP literals, with or
+// without a port such as "[2001:db8::1]:443", are not domain names and are
+// rejected with ErrCodeIPAddress.
+func splitPort(domain string) (host, port string, err error) {
+	if !strings.ContainsRune(domain, ':') {
+		if net.ParseIP(domain) != nil {
+			return "", "", newInputError(domain, ErrCodeIPAddress, "IP addresses are not domain names")
+		}
+		return domain, "", nil
+	}
+	host, port, err = net.SplitHostPort(domain)
+	if err != nil {
+		if net.ParseIP(strings.Trim(domain, "[]")) != nil {
+			return "", "", newInputError(domain, ErrCodeIPAddress, "IP addresses are not domain names")
+		}
+		return "", "", newParseError(domain, ErrCodeInvalidPort, strings.LastIndexByte(domain, ':'), "invalid host and port", nil)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); port == "" || !isDigits(port) || err != nil {
+		e := newParseError(domain, ErrCodeInvalidPort, len(domain)-len(port), fmt.Sprintf("invalid port \"%s\"", port), nil)
+		e.Label = port
+		return "", "", e
+	}
+	if net.ParseIP(host) != nil {
+		return "", "", newInputError(domain, ErrCodeIPAddress, "IP addresses are not domain names")
+	}
+	return host, port, nil
 }
 
+// badChars are the characters validator rejects
+var badChars = []rune{' ', '}', '{', '\'', '\\', '/', '"', ';', ':', '@', '!', '#', '$', '%', '^', '&', '(', ')'}
+
 // validator performs some simple checks on a string
 func validator(domain string) error {
-	var badchars = []rune{' ', '}', '{', '\'', '\\', '/', '"', ';', ':', '@', '!', '#', '$', '%', '^', '&', '(', ')'}
-	for _, char := range badchars {
-		if strings.ContainsRune(domain, char) {
-			return fmt.Errorf("parse \"%s\": domain name cannot contain \"%c\"", domain, char)
+	for _, char := range badChars {
+		if i := strings.IndexRune(domain, char); i >= 0 {
+			return newParseError(domain, ErrCodeInvalidCharacter, i, fmt.Sprintf("domain name cannot contain \"%c\"", char), nil)
 		}
 	}
 	if !strings.ContainsRune(domain, '.') {
-		return fmt.Errorf("parse \"%s\": domain name must contain at least one \".\"", domain)
+		return newParseError(domain, ErrCodeNoDot, 0, "domain name must contain at least one \".\"", nil)
 	}
-	if strings.Contains(domain, "..") {
-		return fmt.Errorf("parse \"%s\": domain name cannot contain two consecutive \"..\"", domain)
-
+	if i := strings.Index(domain, ".."); i >= 0 {
+		return newParseError(domain, ErrCodeEmptyLabel, i+1, "domain name cannot contain two consecutive \"..\"", nil)
 	}
 	return nil
 }
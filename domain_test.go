@@ -1,19 +1,82 @@
 package domain
 
 import (
+	"errors"
+	"os"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+// testList is the suffix list of newTestDomain, a few rules of every kind so
+// tests neither touch the network nor depend on the live public suffix list
+const testList = `// VERSION: test
+// ===BEGIN ICANN DOMAINS===
+com
+net
+org
+edu
+biz
+io
+de
+uk
+co.uk
+jp
+kobe.jp
+*.kobe.jp
+!city.kobe.jp
+ck
+*.ck
+!www.ck
+google
+onion
+arpa
+in-addr.arpa
+ip6.arpa
+xn--fiqs8s
+xn--mgbaam7a8h
+xn--p1ai
+// ===END ICANN DOMAINS===
+// ===BEGIN PRIVATE DOMAINS===
+us.com
+github.io
+blogspot.com
+// ===END PRIVATE DOMAINS===
+`
+
+// newTestDomain returns a Domain holding testList and fails the test if opts
+// are invalid
+func newTestDomain(tb testing.TB, opts ...Option) *Domain {
+	tb.Helper()
+	d, err := NewFromList(strings.NewReader(testList), opts...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return d
+}
+
+// newPSLDomain returns a Domain holding the snapshot of the public suffix
+// list in testdata, for tests that need the whole list
+func newPSLDomain(tb testing.TB, opts ...Option) *Domain {
+	tb.Helper()
+	d, err := NewFromFS(os.DirFS("testdata"), "public_suffix_list.dat", opts...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return d
+}
+
 func TestRecordString(t *testing.T) {
 	tests := []struct {
 		i Record
 		o string
 	}{
-		{i: Record{"www", "example", "com"}, o: "www.example.com"},
-		{i: Record{"EXAMPLEDOMAIN", "GOOGLE", "Co.Uk"}, o: "exampledomain.google.co.uk"},
-		{i: Record{"long.subdomain.for", "example", "us.com"}, o: "long.subdomain.for.example.us.com"},
+		{i: Record{Subdomain: "www", Name: "example", TLD: "com"}, o: "www.example.com"},
+		{i: Record{Subdomain: "EXAMPLEDOMAIN", Name: "GOOGLE", TLD: "Co.Uk"}, o: "exampledomain.google.co.uk"},
+		{i: Record{Subdomain: "long.subdomain.for", Name: "example", TLD: "us.com"}, o: "long.subdomain.for.example.us.com"},
 	}
 	for _, ts := range tests {
 		r := ts.i.String()
@@ -27,17 +90,19 @@ func TestDomainParser(t *testing.T) {
 		i string
 		o *Record
 	}{
-		{i: "WwW.eXample.com", o: &Record{"www", "example", "com"}},
+		{i: "WwW.eXample.com", o: &Record{Subdomain: "www", Name: "example", TLD: "com"}},
 		{i: "bad", o: nil},
 		{i: " .com", o: nil},
 		{i: "a..com", o: nil},
 		{i: "..a.a.a.a", o: nil},
 		{i: "thistlddoes.nonexist", o: nil},
-		{i: "www.super.long.subdomain.hacking.us.com", o: &Record{"www.super.long.subdomain", "hacking", "us.com"}},
-		{i: "blog.google", o: &Record{"", "blog", "google"}},
+		{i: "www.super.long.subdomain.hacking.us.com", o: &Record{Subdomain: "www.super.long.subdomain", Name: "hacking", TLD: "us.com"}},
+		{i: "blog.google", o: &Record{Subdomain: "", Name: "blog", TLD: "google"}},
+		{i: "www。example．co｡uk", o: &Record{Subdomain: "www", Name: "example", TLD: "co.uk"}},
+		{i: "example。com:8080", o: &Record{Name: "example", TLD: "com", Port: "8080"}},
 	}
 
-	ex, _ := New("/tmp/tld.cache")
+	ex := newTestDomain(t)
 	for _, ts := range tests {
 		r, _ := ex.Parse(ts.i)
 		assert.Equal(t, ts.o, r, "These should be equal!")
@@ -54,10 +119,193 @@ func TestDomainLevels(t *testing.T) {
 		{i: "super.long.subdomain.for.example.com", o: []string{"super.long.subdomain.for.example.com", "long.subdomain.for.example.com", "subdomain.for.example.com", "for.example.com", "example.com"}},
 		{i: "naan.example", o: []string{}},
 	}
-	ex, _ := New("/tmp/tld.cache")
+	ex := newTestDomain(t)
 	for _, ts := range tests {
 		r := ex.Levels(ts.i)
 		assert.Equal(t, ts.o, r, "These should be equal!")
 	}
 
 }
+
+func TestDomainLevelsE(t *testing.T) {
+	tests := []struct {
+		i   string
+		o   []string
+		err bool
+	}{
+		{i: "WwW.eXample.com", o: []string{"www.example.com", "example.com"}},
+		{i: "example.com", o: []string{"example.com"}},
+		{i: "naan.example", err: true},
+		{i: "a..com", err: true},
+	}
+	ex := newTestDomain(t)
+	for _, ts := range tests {
+		r, err := ex.LevelsE(ts.i)
+		assert.Equal(t, ts.err, err != nil, ts.i)
+		assert.Equal(t, ts.o, r, "These should be equal!")
+	}
+}
+
+func TestDomainParserPort(t *testing.T) {
+	tests := []struct {
+		i string
+		o *Record
+	}{
+		{i: "example.com:8080", o: &Record{Name: "example", TLD: "com", Port: "8080"}},
+		{i: "WWW.Example.com:443", o: &Record{Subdomain: "www", Name: "example", TLD: "com", Port: "443"}},
+		{i: "[www.example.com]:80", o: &Record{Subdomain: "www", Name: "example", TLD: "com", Port: "80"}},
+		{i: "[2001:db8::1]:443", o: nil},
+		{i: "2001:db8::1", o: nil},
+		{i: "192.0.2.1:80", o: nil},
+		{i: "example.com:", o: nil},
+		{i: "example.com:http", o: nil},
+		{i: "example.com:70000", o: nil},
+		{i: "example.com:+80", o: nil},
+		{i: "example.com:-0", o: nil},
+		{i: "example.com:0x50", o: nil},
+		{i: "example.com:08080", o: &Record{Name: "example", TLD: "com", Port: "08080"}},
+	}
+	ex := newTestDomain(t)
+	for _, ts := range tests {
+		r, _ := ex.Parse(ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+	}
+	// IP literals are not domain names, with or without a port
+	for _, ip := range []string{"[2001:db8::1]:443", "[2001:db8::1]", "2001:db8::1", "192.0.2.1:80", "192.0.2.1"} {
+		_, err := ex.Parse(ip)
+		var pe *ParseError
+		if assert.True(t, errors.As(err, &pe), ip) {
+			assert.Equal(t, ErrCodeIPAddress, pe.Code, ip)
+		}
+	}
+	_, err := ex.Parse("example.com:+80")
+	var pe *ParseError
+	if assert.True(t, errors.As(err, &pe)) {
+		assert.Equal(t, ErrCodeInvalidPort, pe.Code)
+	}
+	assert.Equal(t, []string{"www.example.com", "example.com"}, ex.Levels("www.example.com:8080"))
+}
+
+func TestDomainParserServiceLabels(t *testing.T) {
+	tests := []struct {
+		i string
+		o *Record
+	}{
+		{i: "_dmarc.example.com", o: &Record{Name: "example", TLD: "com", ServiceLabels: []string{"_dmarc"}}},
+		{i: "_443._tcp.www.example.com", o: &Record{Subdomain: "www", Name: "example", TLD: "com", ServiceLabels: []string{"_443", "_tcp"}}},
+		{i: "_acme-challenge.a._b.example.com", o: &Record{Subdomain: "a._b", Name: "example", TLD: "com", ServiceLabels: []string{"_acme-challenge"}}},
+		{i: "www.example.com", o: &Record{Subdomain: "www", Name: "example", TLD: "com"}},
+	}
+	ex := newTestDomain(t, WithServiceLabels())
+	for _, ts := range tests {
+		r, err := ex.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+		assert.Equal(t, ts.i, r.host())
+	}
+}
+
+func TestDomainParserPreserveCase(t *testing.T) {
+	tests := []struct {
+		i string
+		o *Record
+		s string
+	}{
+		{i: "WwW.eXample.com", o: &Record{Subdomain: "WwW", Name: "eXample", TLD: "com"}, s: "www.example.com"},
+		{i: "Api.Dev.Example.CO.UK:8443", o: &Record{Subdomain: "Api.Dev", Name: "Example", TLD: "CO.UK", Port: "8443"}, s: "api.dev.example.co.uk"},
+		{i: "_DMARC.Mail.Example.com", o: &Record{Subdomain: "Mail", Name: "Example", TLD: "com", ServiceLabels: []string{"_DMARC"}}, s: "_dmarc.mail.example.com"},
+	}
+	ex := newTestDomain(t, WithPreserveCase(), WithServiceLabels())
+	for _, ts := range tests {
+		r, err := ex.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+		assert.Equal(t, ts.s, r.String())
+	}
+}
+
+func TestNewFromList(t *testing.T) {
+	list := "// VERSION: 2026-10-01\n// ===BEGIN ICANN DOMAINS===\ncom\nco.uk\n"
+	d, err := NewFromList(strings.NewReader(list), WithServiceLabels())
+	assert.NoError(t, err)
+	assert.Equal(t, "2026-10-01", d.ListVersion().Version)
+	r, err := d.Parse("_dmarc.www.example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "www", Name: "example", TLD: "co.uk", ServiceLabels: []string{"_dmarc"}}, r)
+
+	_, err = NewFromList(strings.NewReader(list), WithAutoReload(nil))
+	assert.IsType(t, &OptionsError{}, err)
+}
+
+func TestUnknownTLDFallback(t *testing.T) {
+	ex := newTestDomain(t, WithUnknownTLDFallback())
+	r, err := ex.Parse("db.payments.corp")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "db", Name: "payments", TLD: "corp", Warnings: []string{`top level domain "corp" is not in the suffix list`}}, r)
+	r, err = ex.Parse("www.example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "www", Name: "example", TLD: "co.uk"}, r)
+	_, err = ex.Parse("corp")
+	assert.Error(t, err)
+
+	// IP addresses are not split into labels
+	for _, ip := range []string{"1.2.3.4", "1.2.3.4:80"} {
+		r, err = ex.Parse(ip)
+		assert.Nil(t, r, ip)
+		var pe *ParseError
+		if assert.True(t, errors.As(err, &pe), ip) {
+			assert.Equal(t, ErrCodeIPAddress, pe.Code, ip)
+		}
+	}
+}
+
+func TestNewFromFS(t *testing.T) {
+	modified := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"psl/list.dat":    {Data: []byte("// VERSION: embedded\ncom\nco.uk\n")},
+		"psl/cache.dat":   {Data: []byte("// DOWNLOADED: 2026-09-01T00:00:00Z\ncom\n"), ModTime: modified},
+		"psl/stamped.dat": {Data: []byte("com\n"), ModTime: modified},
+	}
+	d, err := NewFromFS(fsys, "psl/list.dat")
+	assert.NoError(t, err)
+	assert.Equal(t, ListVersion{Version: "embedded"}, d.ListVersion())
+	r, err := d.Parse("www.example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, "co.uk", r.TLD)
+
+	d, err = NewFromFS(fsys, "psl/cache.dat")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), d.ListVersion().Downloaded)
+	d, err = NewFromFS(fsys, "psl/stamped.dat")
+	assert.NoError(t, err)
+	assert.Equal(t, modified, d.ListVersion().Downloaded)
+
+	_, err = NewFromFS(fsys, "psl/missing.dat")
+	assert.Error(t, err)
+}
+
+func TestWildcardInput(t *testing.T) {
+	tests := []struct {
+		i string
+		o *Record
+		s string
+	}{
+		{i: "*.Example.co.uk", o: &Record{Name: "example", TLD: "co.uk", Wildcard: true}, s: "*.example.co.uk"},
+		{i: "*.api.example.com:443", o: &Record{Subdomain: "api", Name: "example", TLD: "com", Port: "443", Wildcard: true}, s: "*.api.example.com"},
+	}
+	ex := newTestDomain(t)
+	for _, ts := range tests {
+		r, err := ex.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+		assert.Equal(t, ts.s, r.String())
+		assert.Equal(t, ts.s, r.Hostname())
+	}
+	r, _ := ex.Parse("*.example.com")
+	plain, _ := ex.Parse("example.com")
+	assert.False(t, r.Equal(plain))
+	assert.Equal(t, "*.example.com", r.Key().String())
+
+	_, err := ex.Parse("*.co.uk")
+	assert.Error(t, err)
+}
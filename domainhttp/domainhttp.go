@@ -0,0 +1,129 @@
+// Package domainhttp parses the Host header of incoming requests with a
+// domain.Domain, for virtual hosting services that route or restrict
+// requests by registrable domain.
+//
+//	handler = domainhttp.Middleware(d, domainhttp.WithAllowlist("example.com", "example.co.uk"))(handler)
+//
+// Handlers find the parsed host with FromContext.
+package domainhttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/lynxsecurity/domain"
+)
+
+// contextKey is the key the Record is stored under in the request context
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying rec
+func NewContext(ctx context.Context, rec *domain.Record) context.Context {
+	return context.WithValue(ctx, contextKey{}, rec)
+}
+
+// FromContext returns the Record stored by Middleware. It returns false for
+// requests addressed to an IP literal, which have no Record.
+func FromContext(ctx context.Context) (*domain.Record, bool) {
+	rec, ok := ctx.Value(contextKey{}).(*domain.Record)
+	return rec, ok
+}
+
+// Option configures Middleware
+type Option func(*config)
+
+// config holds the settings collected from a list of Option values
+type config struct {
+	allowlist []string
+	allowIP   bool
+}
+
+// WithAllowlist only lets requests through whose host is one of the given
+// registrable domains or a subdomain of one, everything else is answered
+// with 421 Misdirected Request. It can be given more than once.
+func WithAllowlist(registrable ...string) Option {
+	return func(c *config) {
+		c.allowlist = append(c.allowlist, registrable...)
+	}
+}
+
+// WithIPLiterals lets requests addressed to an IP literal, such as health
+// checks, through an allowlist. They reach the handler without a Record.
+func WithIPLiterals() Option {
+	return func(c *config) {
+		c.allowIP = true
+	}
+}
+
+// Middleware parses the Host of every request with d, including any port and
+// bracketed IPv6 literals, and stores the Record in the request context.
+// Hosts that do not parse are answered with 400 Bad Request. Requests to an
+// IP literal pass through without a Record unless an allowlist is set.
+func Middleware(d *domain.Domain, opts ...Option) func(http.Handler) http.Handler {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	var allowed map[string]bool
+	if len(c.allowlist) > 0 {
+		allowed = make(map[string]bool, len(c.allowlist))
+		for _, name := range c.allowlist {
+			allowed[canonical(d, name)] = true
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host, port := splitHost(r.Host)
+			if host == "" {
+				http.Error(w, "missing host", http.StatusBadRequest)
+				return
+			}
+			if net.ParseIP(host) != nil {
+				if allowed != nil && !c.allowIP {
+					http.Error(w, "host not allowed", http.StatusMisdirectedRequest)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			rec, err := d.Parse(host)
+			if err != nil {
+				http.Error(w, "invalid host", http.StatusBadRequest)
+				return
+			}
+			rec.Port = port
+			if allowed != nil && !allowed[registrable(rec)] {
+				http.Error(w, "host not allowed", http.StatusMisdirectedRequest)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), rec)))
+		})
+	}
+}
+
+// splitHost splits the port off a Host header and removes the brackets of
+// an IPv6 literal
+func splitHost(hostport string) (host, port string) {
+	host = hostport
+	if h, p, err := net.SplitHostPort(hostport); err == nil {
+		host, port = h, p
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	return strings.TrimSuffix(host, "."), port
+}
+
+// registrable returns the lowercase registrable domain of rec
+func registrable(rec *domain.Record) string {
+	return strings.ToLower(rec.Name + "." + rec.TLD)
+}
+
+// canonical returns the form of an allowlist entry Middleware compares
+// against, so Unicode and punycode spellings of a name are the same entry
+func canonical(d *domain.Domain, name string) string {
+	if rec, err := d.Parse(name); err == nil {
+		return registrable(rec)
+	}
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
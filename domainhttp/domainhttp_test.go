@@ -0,0 +1,54 @@
+package domainhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lynxsecurity/domain/domaintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	d := domaintest.New(t)
+	var got string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = "no record"
+		if rec, ok := FromContext(r.Context()); ok {
+			got = rec.Subdomain + "|" + rec.Name + "|" + rec.TLD + "|" + rec.Port
+		}
+	})
+	serve := func(h http.Handler, host string) int {
+		got = ""
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = host
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	open := Middleware(d)(handler)
+	assert.Equal(t, http.StatusOK, serve(open, "www.example.co.uk:8443"))
+	assert.Equal(t, "www|example|co.uk|8443", got)
+	assert.Equal(t, http.StatusOK, serve(open, "Shop.Example.com."))
+	assert.Equal(t, "shop|example|com|", got)
+	assert.Equal(t, http.StatusOK, serve(open, "[2001:db8::1]:8080"))
+	assert.Equal(t, "no record", got)
+	assert.Equal(t, http.StatusOK, serve(open, "192.0.2.1"))
+	assert.Equal(t, "no record", got)
+	assert.Equal(t, http.StatusBadRequest, serve(open, "localhost"))
+	assert.Equal(t, http.StatusBadRequest, serve(open, ""))
+	assert.Empty(t, got)
+
+	restricted := Middleware(d, WithAllowlist("example.com", "www.example.co.uk"))(handler)
+	assert.Equal(t, http.StatusOK, serve(restricted, "api.example.com"))
+	assert.Equal(t, http.StatusOK, serve(restricted, "example.com:80"))
+	assert.Equal(t, http.StatusOK, serve(restricted, "other.example.co.uk"))
+	assert.Equal(t, http.StatusMisdirectedRequest, serve(restricted, "example.org"))
+	assert.Equal(t, http.StatusMisdirectedRequest, serve(restricted, "[::1]"))
+	assert.Empty(t, got)
+
+	withIP := Middleware(d, WithAllowlist("example.com"), WithIPLiterals())(handler)
+	assert.Equal(t, http.StatusOK, serve(withIP, "[::1]"))
+	assert.Equal(t, "no record", got)
+}
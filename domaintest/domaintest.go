@@ -0,0 +1,66 @@
+// Package domaintest provides a Domain with a small, fixed rule set and
+// Record constructors for tests, so they neither touch the file system or
+// network nor depend on the contents of the live public suffix list.
+package domaintest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lynxsecurity/domain"
+)
+
+// List is the suffix list of the Domain returned by New. It covers the rule
+// kinds that matter in tests: single and multi label suffixes, wildcards,
+// exceptions, private suffixes and an IDN TLD in both spellings.
+const List = `// VERSION: domaintest
+// ===BEGIN ICANN DOMAINS===
+com
+net
+org
+io
+de
+uk
+co.uk
+ac.uk
+jp
+*.kawasaki.jp
+!city.kawasaki.jp
+ck
+*.ck
+!www.ck
+xn--p1ai
+// ===END ICANN DOMAINS===
+// ===BEGIN PRIVATE DOMAINS===
+github.io
+appspot.com
+blogspot.com
+// ===END PRIVATE DOMAINS===
+`
+
+// New returns an in-memory Domain holding List, opts are passed on to
+// domain.NewFromList. It fails the test if the options are invalid.
+func New(tb testing.TB, opts ...domain.Option) *domain.Domain {
+	tb.Helper()
+	d, err := domain.NewFromList(strings.NewReader(List), opts...)
+	if err != nil {
+		tb.Fatalf("domaintest: %v", err)
+	}
+	return d
+}
+
+// Parse parses host with a Domain holding List and fails the test if it
+// cannot be parsed
+func Parse(tb testing.TB, host string) *domain.Record {
+	tb.Helper()
+	rec, err := New(tb).Parse(host)
+	if err != nil {
+		tb.Fatalf("domaintest: %v", err)
+	}
+	return rec
+}
+
+// Record builds a Record from its parts without parsing, for expected values
+func Record(subdomain, name, tld string) *domain.Record {
+	return &domain.Record{Subdomain: subdomain, Name: name, TLD: tld}
+}
@@ -0,0 +1,36 @@
+package domaintest
+
+import (
+	"testing"
+
+	"github.com/lynxsecurity/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomain(t *testing.T) {
+	d := New(t, domain.WithServiceLabels())
+	tests := []struct {
+		i string
+		o *domain.Record
+	}{
+		{i: "www.example.co.uk", o: Record("www", "example", "co.uk")},
+		{i: "a.b.kawasaki.jp", o: Record("", "a", "b.kawasaki.jp")},
+		{i: "city.kawasaki.jp", o: Record("", "city", "kawasaki.jp")},
+		{i: "user.github.io", o: Record("", "user", "github.io")},
+		{i: "пример.рф", o: Record("", "пример", "рф")},
+	}
+	for _, ts := range tests {
+		r, err := d.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+	}
+	assert.Equal(t, "domaintest", d.ListVersion().Version)
+	assert.Equal(t, Record("mail", "example", "com"), Parse(t, "mail.example.com"))
+
+	sections := map[string]domain.Section{}
+	for _, r := range d.Rules() {
+		sections[r.String()] = r.Section
+	}
+	assert.Equal(t, domain.SectionICANN, sections["co.uk"])
+	assert.Equal(t, domain.SectionPrivate, sections["github.io"])
+}
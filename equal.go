@@ -0,0 +1,56 @@
+package domain
+
+import "strings"
+
+// RecordKey is the canonical form of a Record, comparable with == and
+// usable as a map key for deduplication
+type RecordKey struct {
+	// Subdomain includes any service labels
+	Subdomain, Name, TLD, Port string
+}
+
+// Key returns the canonical form of the record: every part lowercased, in its
+// punycode form and without surrounding dots
+func (r *Record) Key() RecordKey {
+	sub := r.Subdomain
+	if len(r.ServiceLabels) > 0 || r.Wildcard {
+		sub = strings.TrimSuffix(r.servicePrefix()+sub, ".")
+	}
+	return RecordKey{
+		Subdomain: canonicalPart(sub),
+		Name:      canonicalPart(r.Name),
+		TLD:       canonicalPart(r.TLD),
+		Port:      r.Port,
+	}
+}
+
+// String formats the key as a hostname, followed by the port if there is one
+func (k RecordKey) String() string {
+	h := k.Name + "." + k.TLD
+	if k.Subdomain != "" {
+		h = k.Subdomain + "." + h
+	}
+	if k.Port != "" {
+		h += ":" + k.Port
+	}
+	return h
+}
+
+// Equal reports whether two records name the same host with the same public
+// suffix boundary and port, ignoring case, Unicode versus punycode spelling
+// and trailing dots
+func (r *Record) Equal(other *Record) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	return r.Key() == other.Key()
+}
+
+// canonicalPart lowercases part, trims its dots and converts it to punycode
+func canonicalPart(part string) string {
+	part = strings.ToLower(strings.Trim(part, "."))
+	if ascii, err := toASCII(part); err == nil {
+		return ascii
+	}
+	return part
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordEqual(t *testing.T) {
+	a := &Record{Subdomain: "WWW", Name: "Bücher", TLD: "DE."}
+	b := &Record{Subdomain: "www", Name: "xn--bcher-kva", TLD: "de"}
+	assert.True(t, a.Equal(b))
+	assert.Equal(t, a.Key(), b.Key())
+	assert.Equal(t, "www.xn--bcher-kva.de", a.Key().String())
+
+	// the suffix boundary, port and service labels are part of the identity
+	assert.False(t, a.Equal(&Record{Name: "www", TLD: "xn--bcher-kva.de"}))
+	assert.False(t, b.Equal(&Record{Subdomain: "www", Name: "xn--bcher-kva", TLD: "de", Port: "443"}))
+	svc := &Record{Subdomain: "mail", Name: "example", TLD: "com", ServiceLabels: []string{"_dmarc"}}
+	assert.True(t, svc.Equal(&Record{Subdomain: "_DMARC.mail", Name: "example", TLD: "com"}))
+
+	var none *Record
+	assert.False(t, a.Equal(nil))
+	assert.True(t, none.Equal(nil))
+
+	seen := map[RecordKey]bool{a.Key(): true}
+	assert.True(t, seen[b.Key()])
+}
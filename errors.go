@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseErrorCode classifies why a name could not be parsed
+type ParseErrorCode int
+
+const (
+	// ErrCodeInvalidCharacter means the name holds a character that cannot
+	// appear in a hostname
+	ErrCodeInvalidCharacter ParseErrorCode = iota + 1
+	// ErrCodeNoDot means the name is a single label
+	ErrCodeNoDot
+	// ErrCodeEmptyLabel means the name has an empty label, such as "a..com"
+	ErrCodeEmptyLabel
+	// ErrCodeUnknownTLD means no suffix rule matches the name
+	ErrCodeUnknownTLD
+	// ErrCodeMissingName means the name is a public suffix itself
+	ErrCodeMissingName
+	// ErrCodeIPAddress means an IP address was given instead of a name
+	ErrCodeIPAddress
+	// ErrCodeInvalidPort means a "host:port" input has a malformed port
+	ErrCodeInvalidPort
+	// ErrCodeNotDelegated means the TLD does not exist in the root zone,
+	// see WithRootZone
+	ErrCodeNotDelegated
+	// ErrCodeUnlistedSuffix means the TLD is delegated but missing from the
+	// suffix list, see WithRootZone
+	ErrCodeUnlistedSuffix
+	// ErrCodeSpecialUse means the name is under a special-use domain that
+	// the Domain rejects, see WithSpecialUse
+	ErrCodeSpecialUse
+	// ErrCodeInvisibleCharacter means the name holds an invisible or control
+	// character, see WithRejectInvisible
+	ErrCodeInvisibleCharacter
+	// ErrCodeInputTooLarge means the input exceeds the length or label count
+	// limit, see WithInputLimits
+	ErrCodeInputTooLarge
+	// ErrCodeIDNA means a label fails the IDNA profile, see WithIDNAProfile
+	ErrCodeIDNA
+	// ErrCodeLabelTooLong means a label is longer than 63 characters in its
+	// ASCII form, reported by Validate
+	ErrCodeLabelTooLong
+	// ErrCodeNameTooLong means the name is longer than 253 characters in its
+	// ASCII form, reported by Validate
+	ErrCodeNameTooLong
+
+	// numErrCodes is one past the last code, new codes go above it
+	numErrCodes
+)
+
+// String returns a short name for the code
+func (c ParseErrorCode) String() string {
+	switch c {
+	case ErrCodeInvalidCharacter:
+		return "invalid-character"
+	case ErrCodeNoDot:
+		return "no-dot"
+	case ErrCodeEmptyLabel:
+		return "empty-label"
+	case ErrCodeUnknownTLD:
+		return "unknown-tld"
+	case ErrCodeMissingName:
+		return "missing-name"
+	case ErrCodeIPAddress:
+		return "ip-address"
+	case ErrCodeInvalidPort:
+		return "invalid-port"
+	case ErrCodeNotDelegated:
+		return "not-delegated"
+	case ErrCodeUnlistedSuffix:
+		return "unlisted-suffix"
+	case ErrCodeSpecialUse:
+		return "special-use"
+	case ErrCodeInvisibleCharacter:
+		return "invisible-character"
+	case ErrCodeInputTooLarge:
+		return "input-too-large"
+	case ErrCodeIDNA:
+		return "idna"
+	case ErrCodeLabelTooLong:
+		return "label-too-long"
+	case ErrCodeNameTooLong:
+		return "name-too-long"
+	}
+	return "unknown"
+}
+
+// ParseError is the error Parse returns, locating the problem in the input so
+// that a UI can highlight it
+type ParseError struct {
+	// Input is the name that was parsed, lowercased and with any port
+	// removed, or the whole input for errors about the port
+	Input string
+	// Label is the label, or port, the problem is in, empty when the problem
+	// is not confined to one label
+	Label string
+	// Offset is the byte offset of the problem in Input
+	Offset int
+	Code   ParseErrorCode
+	// Err is the sentinel error behind the problem, such as ErrNotDelegated,
+	// nil when there is none
+	Err error
+
+	msg string
+}
+
+// Error formats the error the way Parse always has
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse \"%s\": %s", e.Input, e.msg)
+}
+
+// Unwrap returns Err, so errors.Is matches the sentinel errors
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError creates a ParseError whose message is the sentinel err when
+// msg is empty
+func newParseError(input string, code ParseErrorCode, offset int, msg string, err error) *ParseError {
+	if msg == "" && err != nil {
+		msg = err.Error()
+	}
+	return &ParseError{Input: input, Label: labelAt(input, offset), Offset: offset, Code: code, Err: err, msg: msg}
+}
+
+// newInputError creates a ParseError about the input as a whole
+func newInputError(input string, code ParseErrorCode, msg string) *ParseError {
+	return &ParseError{Input: input, Code: code, msg: msg}
+}
+
+// labelAt returns the dot separated label of name that contains offset
+func labelAt(name string, offset int) string {
+	if offset < 0 || offset > len(name) {
+		return ""
+	}
+	start := strings.LastIndexByte(name[:offset], '.') + 1
+	end := strings.IndexByte(name[offset:], '.')
+	if end < 0 {
+		return name[start:]
+	}
+	return name[start : offset+end]
+}
+
+// lastLabelOffset returns the offset of the last label of name
+func lastLabelOffset(name string) int {
+	return strings.LastIndexByte(strings.TrimSuffix(name, "."), '.') + 1
+}
+
+// labelOffset returns the offset of labels[i] in the name the labels were
+// split from
+func labelOffset(labels []string, i int) int {
+	offset := 0
+	for _, label := range labels[:i] {
+		offset += len(label) + 1
+	}
+	return offset
+}
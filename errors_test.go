@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		i      string
+		code   ParseErrorCode
+		label  string
+		offset int
+		msg    string
+	}{
+		{i: "www.exa mple.com", code: ErrCodeInvalidCharacter, label: "exa mple", offset: 7, msg: `parse "www.exa mple.com": domain name cannot contain " "`},
+		{i: "localhost", code: ErrCodeNoDot, label: "localhost", offset: 0},
+		{i: "a..example.com", code: ErrCodeEmptyLabel, label: "", offset: 2},
+		{i: ".example.com", code: ErrCodeEmptyLabel, label: "", offset: 0},
+		{i: "www.Example.nonexist", code: ErrCodeUnknownTLD, label: "nonexist", offset: 12, msg: `parse "www.example.nonexist": top level domain does not exist`},
+		{i: "co.uk", code: ErrCodeMissingName, label: "", offset: 0},
+		{i: "example.com:99999", code: ErrCodeInvalidPort, label: "99999", offset: 12},
+		{i: "10.0.0.1:80", code: ErrCodeIPAddress, offset: 0, label: ""},
+	}
+	ex := newTestDomain(t)
+	for _, ts := range tests {
+		_, err := ex.Parse(ts.i)
+		var pe *ParseError
+		if !assert.True(t, errors.As(err, &pe), ts.i) {
+			continue
+		}
+		assert.Equal(t, ts.code, pe.Code, ts.i)
+		assert.Equal(t, ts.label, pe.Label, ts.i)
+		assert.Equal(t, ts.offset, pe.Offset, ts.i)
+		if ts.msg != "" {
+			assert.EqualError(t, err, ts.msg)
+		}
+	}
+
+	reject := newTestDomain(t, WithSpecialUse(SpecialUseReject))
+	_, err := reject.Parse("nas.home.arpa.")
+	var pe *ParseError
+	if assert.True(t, errors.As(err, &pe)) {
+		assert.Equal(t, ErrCodeSpecialUse, pe.Code)
+		assert.Equal(t, "home.arpa", pe.Label)
+		assert.Equal(t, 4, pe.Offset)
+		assert.Equal(t, "special-use", pe.Code.String())
+	}
+	assert.True(t, errors.Is(err, ErrSpecialUse))
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PartitionBy selects how ExportPartitioned splits its output
+type PartitionBy int
+
+const (
+	// PartitionByTLD writes one partition per public suffix, "co.uk" for example
+	PartitionByTLD PartitionBy = iota
+	// PartitionByFirstLetter writes one partition per first character of the
+	// registrable domain's name, non alphanumeric names go to "_"
+	PartitionByFirstLetter
+)
+
+// PartitionOpener opens the destination of a partition, such as a file or an
+// object store upload. It is called once per partition.
+type PartitionOpener func(partition string) (io.WriteCloser, error)
+
+// DirPartitions returns a PartitionOpener that writes each partition to
+// dir/<partition>.jsonl
+func DirPartitions(dir string) PartitionOpener {
+	return func(partition string) (io.WriteCloser, error) {
+		return os.Create(filepath.Join(dir, partition+".jsonl"))
+	}
+}
+
+// exportRow is the JSON line written for every parsed host
+type exportRow struct {
+	Host      string `json:"host"`
+	Subdomain string `json:"subdomain"`
+	Name      string `json:"name"`
+	TLD       string `json:"tld"`
+	Port      string `json:"port,omitempty"`
+}
+
+// partitionWriter buffers the output of one partition
+type partitionWriter struct {
+	dst io.WriteCloser
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+// ExportPartitioned parses one host per line from r, like ParseStream, and
+// writes every parsed host as a JSON line into the partition chosen by by.
+// Hosts that fail to parse are counted in the stats but not exported.
+func (d *Domain) ExportPartitioned(r io.Reader, by PartitionBy, open PartitionOpener) (BatchStats, error) {
+	partitions := make(map[string]*partitionWriter)
+	var exportErr error
+	stats, err := d.ParseStream(r, func(res BatchResult) {
+		if res.Err != nil || exportErr != nil {
+			return
+		}
+		key := partitionKey(res.Record, by)
+		w, ok := partitions[key]
+		if !ok {
+			dst, err := open(key)
+			if err != nil {
+				exportErr = fmt.Errorf("export: could not open partition \"%s\": %v", key, err)
+				return
+			}
+			buf := bufio.NewWriter(dst)
+			w = &partitionWriter{dst: dst, buf: buf, enc: json.NewEncoder(buf)}
+			partitions[key] = w
+		}
+		row := exportRow{Host: res.Host, Subdomain: res.Record.Subdomain, Name: res.Record.Name, TLD: res.Record.TLD, Port: res.Record.Port}
+		if err := w.enc.Encode(row); err != nil {
+			exportErr = fmt.Errorf("export: could not write partition \"%s\": %v", key, err)
+		}
+	})
+	for key, w := range partitions {
+		if ferr := w.buf.Flush(); ferr != nil && exportErr == nil {
+			exportErr = fmt.Errorf("export: could not write partition \"%s\": %v", key, ferr)
+		}
+		if cerr := w.dst.Close(); cerr != nil && exportErr == nil {
+			exportErr = fmt.Errorf("export: could not close partition \"%s\": %v", key, cerr)
+		}
+	}
+	if err != nil {
+		return stats, err
+	}
+	return stats, exportErr
+}
+
+// partitionKey returns the partition a record is exported to
+func partitionKey(rec *Record, by PartitionBy) string {
+	if by == PartitionByTLD {
+		return strings.ToLower(rec.TLD)
+	}
+	c, _ := utf8.DecodeRuneInString(strings.ToLower(rec.Name))
+	if unicode.IsLetter(c) || unicode.IsDigit(c) {
+		return string(c)
+	}
+	return "_"
+}
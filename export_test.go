@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportPartitioned(t *testing.T) {
+	dir, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d := newDomain("", strings.NewReader("com\nuk\nco.uk\n"), &options{})
+	input := "www.example.com\nexample.co.uk\nbad\nzebra.com:8080\n"
+	stats, err := d.ExportPartitioned(strings.NewReader(input), PartitionByTLD, DirPartitions(dir))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, stats.Parsed)
+	assert.Equal(t, 1, stats.Failed)
+
+	com, err := ioutil.ReadFile(filepath.Join(dir, "com.jsonl"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"host":"www.example.com","subdomain":"www","name":"example","tld":"com"}
+{"host":"zebra.com:8080","subdomain":"","name":"zebra","tld":"com","port":"8080"}
+`, string(com))
+	couk, err := ioutil.ReadFile(filepath.Join(dir, "co.uk.jsonl"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"host":"example.co.uk","subdomain":"","name":"example","tld":"co.uk"}
+`, string(couk))
+}
+
+func TestPartitionKey(t *testing.T) {
+	assert.Equal(t, "e", partitionKey(&Record{Name: "Example", TLD: "com"}, PartitionByFirstLetter))
+	assert.Equal(t, "9", partitionKey(&Record{Name: "9gag", TLD: "com"}, PartitionByFirstLetter))
+	assert.Equal(t, "_", partitionKey(&Record{Name: "-x", TLD: "com"}, PartitionByFirstLetter))
+	assert.Equal(t, "co.uk", partitionKey(&Record{Name: "x", TLD: "CO.UK"}, PartitionByTLD))
+}
@@ -0,0 +1,62 @@
+package domain
+
+import "strings"
+
+// HostingProviders maps well-known hosting and SaaS suffixes to the name of
+// the service behind them, it is the mapping WithHostingProviders starts from
+var HostingProviders = map[string]string{
+	"s3.amazonaws.com":         "Amazon S3",
+	"s3-website.amazonaws.com": "Amazon S3",
+	"cloudfront.net":           "Amazon CloudFront",
+	"elasticbeanstalk.com":     "AWS Elastic Beanstalk",
+	"azurewebsites.net":        "Microsoft Azure",
+	"cloudapp.net":             "Microsoft Azure",
+	"blob.core.windows.net":    "Microsoft Azure",
+	"azureedge.net":            "Microsoft Azure",
+	"trafficmanager.net":       "Microsoft Azure",
+	"appspot.com":              "Google App Engine",
+	"web.app":                  "Firebase Hosting",
+	"firebaseapp.com":          "Firebase Hosting",
+	"storage.googleapis.com":   "Google Cloud Storage",
+	"github.io":                "GitHub Pages",
+	"gitlab.io":                "GitLab Pages",
+	"herokuapp.com":            "Heroku",
+	"netlify.app":              "Netlify",
+	"vercel.app":               "Vercel",
+	"pages.dev":                "Cloudflare Pages",
+	"workers.dev":              "Cloudflare Workers",
+	"fastly.net":               "Fastly",
+	"myshopify.com":            "Shopify",
+	"zendesk.com":              "Zendesk",
+	"wordpress.com":            "WordPress.com",
+}
+
+// WithHostingProviders sets Record.Provider for names under a suffix of
+// HostingProviders, with extra added to or overriding its entries. Asset
+// inventories use it to tell their own domains from tenants on a provider's
+// domain.
+func WithHostingProviders(extra map[string]string) Option {
+	return func(o *options) {
+		o.hosting = make(map[string]string, len(HostingProviders)+len(extra))
+		for suffix, provider := range HostingProviders {
+			o.hosting[suffix] = provider
+		}
+		for suffix, provider := range extra {
+			o.hosting[strings.ToLower(strings.Trim(suffix, "."))] = provider
+		}
+	}
+}
+
+// hostingProvider returns the provider of the longest hosting suffix of the
+// lowercase labels, or "" if there is none
+func (d *Domain) hostingProvider(labels []string) string {
+	if d.opts.hosting == nil {
+		return ""
+	}
+	for i := range labels {
+		if provider, ok := d.opts.hosting[strings.Join(labels[i:], ".")]; ok {
+			return provider
+		}
+	}
+	return ""
+}
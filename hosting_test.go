@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostingProviders(t *testing.T) {
+	tests := []struct {
+		i        string
+		provider string
+	}{
+		{i: "assets.S3.amazonaws.com", provider: "Amazon S3"},
+		{i: "d111111abcdef8.cloudfront.net", provider: "Amazon CloudFront"},
+		{i: "shop.azurewebsites.net", provider: "Microsoft Azure"},
+		{i: "docs.lynxsecurity.github.io", provider: "GitHub Pages"},
+		{i: "status.example.com", provider: "Example Status"},
+		{i: "www.example.com", provider: ""},
+	}
+	ex := newTestDomain(t, WithHostingProviders(map[string]string{".status.example.com.": "Example Status"}))
+	plain := newTestDomain(t)
+	for _, ts := range tests {
+		r, err := ex.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.provider, r.Provider, ts.i)
+		r, err = plain.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Empty(t, r.Provider, ts.i)
+	}
+}
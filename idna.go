@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// IDNAProfile selects how strictly Parse validates internationalized labels
+type IDNAProfile int
+
+const (
+	// IDNALookup is the default profile: any label that passes the
+	// character validator is accepted, as resolvers do
+	IDNALookup IDNAProfile = iota
+	// IDNARegistration applies the IDNA2008 rules for registering names
+	// (RFC 5891 section 4, RFC 5892 and RFC 5893) with the Registration
+	// profile of golang.org/x/net/idna plus the RFC 5892 rules it leaves
+	// out, to every label in its Unicode form for "xn--" labels. It rejects
+	// DISALLOWED and unassigned code points (so also "_" and symbols),
+	// labels that are not canonical punycode, misplaced hyphens, leading
+	// combining marks, CONTEXTJ and CONTEXTO characters out of context,
+	// labels breaking the bidi rule and names over the DNS length limits.
+	// Names are checked as given with only ASCII case folded, so characters
+	// the UTS #46 mapping would change, such as "Ü" or "ﬁ", fail. Zero width
+	// joiners and non-joiners are kept for the CONTEXTJ rules instead of
+	// being stripped as invisible.
+	IDNARegistration
+)
+
+// WithIDNAProfile sets the validation profile of internationalized labels,
+// names failing it are rejected with ErrCodeIDNA
+func WithIDNAProfile(p IDNAProfile) Option {
+	return func(o *options) {
+		o.idnaProfile = p
+	}
+}
+
+// checkRegistration applies the IDNARegistration profile to the lowercase
+// labels of a name, returning the index of the first failing label. The
+// Registration profile of x/net/idna checks code points against the UTS #46
+// table, hyphens, combining marks, CONTEXTJ, the bidi rule and lengths,
+// checkRegistrationLabel adds the RFC 5892 rules it leaves out. Names failing
+// only as a whole, such as over the length limits, report label 0.
+func checkRegistration(labels []string) (int, error) {
+	if _, err := idna.Registration.ToASCII(strings.Join(labels, ".")); err != nil {
+		for i, label := range labels {
+			if _, lerr := idna.Registration.ToASCII(label); lerr != nil {
+				return i, lerr
+			}
+		}
+		return 0, err
+	}
+	for i, label := range labels {
+		if err := checkRegistrationLabel(label); err != nil {
+			return i, err
+		}
+	}
+	return 0, nil
+}
+
+// checkRegistrationLabel applies the rules of RFC 5892 the UTS #46 table does
+// not: "xn--" labels must be the canonical encoding of a non-ASCII label,
+// code points must be letters, marks or digits (the LetterDigits category,
+// which excludes symbols such as U+2665 that UTS #46 keeps as NV8) and
+// CONTEXTO characters must be in context
+func checkRegistrationLabel(label string) error {
+	u := label
+	if strings.HasPrefix(label, acePrefix) {
+		var err error
+		if u, err = toUnicode(label); err != nil {
+			return err
+		}
+		if isASCII(u) {
+			return fmt.Errorf("\"%s\" encodes an ASCII label", label)
+		}
+		if enc, err := toASCII(u); err != nil || enc != label {
+			return fmt.Errorf("\"%s\" is not the canonical encoding of \"%s\"", label, u)
+		}
+	}
+	runes := []rune(u)
+	arabicIndic, extendedArabicIndic := false, false
+	for i, r := range runes {
+		if !(unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) || r == '-' || joiner(r) || contextO(r)) {
+			return fmt.Errorf("label \"%s\" contains the disallowed character %U", u, r)
+		}
+		if contextO(r) && !contextOValid(runes, i) {
+			return fmt.Errorf("label \"%s\" contains %U out of context", u, r)
+		}
+		arabicIndic = arabicIndic || (r >= '٠' && r <= '٩')
+		extendedArabicIndic = extendedArabicIndic || (r >= '۰' && r <= '۹')
+	}
+	if arabicIndic && extendedArabicIndic {
+		return fmt.Errorf("label \"%s\" mixes Arabic-Indic and extended Arabic-Indic digits", u)
+	}
+	return nil
+}
+
+// contextO reports whether r is one of the CONTEXTO code points of RFC 5892
+// appendix A that can appear in hostnames
+func contextO(r rune) bool {
+	switch r {
+	case '·', '͵', '׳', '״', '・':
+		return true
+	}
+	return false
+}
+
+// contextOValid applies the RFC 5892 appendix A rule of the CONTEXTO code
+// point at runes[i]
+func contextOValid(runes []rune, i int) bool {
+	switch runes[i] {
+	case '·': // middle dot, only in Catalan "l·l"
+		return i > 0 && i+1 < len(runes) && runes[i-1] == 'l' && runes[i+1] == 'l'
+	case '͵': // Greek keraia, followed by Greek
+		return i+1 < len(runes) && unicode.Is(unicode.Greek, runes[i+1])
+	case '׳', '״': // Hebrew geresh and gershayim, after Hebrew
+		return i > 0 && unicode.Is(unicode.Hebrew, runes[i-1])
+	case '・': // katakana middle dot, with Japanese script in the label
+		for _, r := range runes {
+			if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) && r != '・' {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// acePrefix marks a punycode encoded label
+const acePrefix = "xn--"
+
+// isASCII checks whether s only holds ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// toASCII converts every non-ASCII label of name to its "xn--" form with the
+// Punycode profile of x/net/idna, which neither maps nor validates labels
+func toASCII(name string) (string, error) {
+	if isASCII(name) {
+		return name, nil
+	}
+	return idna.Punycode.ToASCII(name)
+}
+
+// toUnicode converts every "xn--" label of name to its Unicode form with the
+// Punycode profile of x/net/idna
+func toUnicode(name string) (string, error) {
+	if !strings.Contains(name, acePrefix) {
+		return name, nil
+	}
+	return idna.Punycode.ToUnicode(name)
+}
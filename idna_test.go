@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDNARegistration(t *testing.T) {
+	tests := []struct {
+		i     string
+		label string
+		msg   string
+	}{
+		{i: "www.bücher.de"},
+		{i: "www.xn--bcher-kva.de"},
+		{i: "l·l.example.com"},
+		{i: "xn--4gbrim.xn--mgbaam7a8h"},
+		{i: "straße.de"},
+		{i: "xn--zca.de"},
+		{i: "क्\u200cष.example.com"},
+		{i: "ﬁsh.com", label: "ﬁsh", msg: "idna: disallowed rune U+FB01"},
+		{i: "WWW.Bücher.DE"},
+		{i: "bÜcher.de", label: "bÜcher", msg: "idna: disallowed rune U+00DC"},
+		{i: "a\u200cb.example.com", label: "a\u200cb", msg: `idna: invalid label "a\u200cb"`},
+		{i: "a\u200db.example.com", label: "a\u200db", msg: `idna: invalid label "a\u200db"`},
+		{i: "xn--abc-.de", label: "xn--abc-", msg: `"xn--abc-" encodes an ASCII label`},
+		{i: "_dmarc.example.com", label: "_dmarc", msg: "idna: disallowed rune U+005F"},
+		{i: "-www.example.com", label: "-www", msg: `idna: invalid label "-www"`},
+		{i: "ab--c.example.com", label: "ab--c", msg: `idna: invalid label "ab--c"`},
+		{i: "a·b.example.com", label: "a·b", msg: `label "a·b" contains U+00B7 out of context`},
+		{i: "♥.example.com", label: "♥", msg: `label "♥" contains the disallowed character U+2665`},
+		{i: "aموقع.com", label: "aموقع", msg: `idna: invalid label "aموقع"`},
+	}
+	strict := newTestDomain(t, WithIDNAProfile(IDNARegistration))
+	lookup := newTestDomain(t)
+	for _, ts := range tests {
+		_, err := strict.Parse(ts.i)
+		if ts.msg == "" {
+			assert.NoError(t, err, ts.i)
+			continue
+		}
+		if assert.IsType(t, &ParseError{}, err, ts.i) {
+			pe := err.(*ParseError)
+			assert.Equal(t, ErrCodeIDNA, pe.Code, ts.i)
+			assert.Equal(t, ts.label, pe.Label, ts.i)
+			assert.Equal(t, `parse "`+ts.i+`": `+ts.msg, pe.Error())
+		}
+		if ts.label != "♥" {
+			_, err = lookup.Parse(ts.i)
+			assert.NoError(t, err, ts.i)
+		}
+	}
+}
+
+func TestIDNRuleForms(t *testing.T) {
+	tests := []struct {
+		i string
+		o *Record
+	}{
+		{i: "example.中国", o: &Record{Name: "example", TLD: "中国"}},
+		{i: "example.xn--fiqs8s", o: &Record{Name: "example", TLD: "xn--fiqs8s"}},
+		{i: "www.example.XN--P1AI", o: &Record{Subdomain: "www", Name: "example", TLD: "xn--p1ai"}},
+	}
+	ex := newTestDomain(t)
+	for _, ts := range tests {
+		r, err := ex.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+	}
+}
+
+func TestPunycodeForms(t *testing.T) {
+	tests := []struct {
+		unicode, ascii string
+	}{
+		{unicode: "中国", ascii: "xn--fiqs8s"},
+		{unicode: "www.bücher.de", ascii: "www.xn--bcher-kva.de"},
+		{unicode: "рф", ascii: "xn--p1ai"},
+		{unicode: "ドメイン名例", ascii: "xn--eckwd4c7cu47r2wf"},
+		{unicode: "*.example.com", ascii: "*.example.com"},
+	}
+	for _, ts := range tests {
+		ascii, err := toASCII(ts.unicode)
+		assert.NoError(t, err)
+		assert.Equal(t, ts.ascii, ascii, ts.unicode)
+		u, err := toUnicode(ts.ascii)
+		assert.NoError(t, err)
+		assert.Equal(t, ts.unicode, u, ts.ascii)
+	}
+	_, err := toUnicode("xn--a-b!")
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"iter"
+	"strings"
+)
+
+// LevelsSeq yields the same levels as LevelsE, longest first, without
+// building a slice. Nothing is yielded if host cannot be parsed, use LevelsE
+// when the error matters.
+func (d *Domain) LevelsSeq(host string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		rec, err := d.Parse(host)
+		if err != nil {
+			return
+		}
+		name := rec.Name + "." + rec.TLD
+		if rec.Subdomain != "" {
+			name = rec.Subdomain + "." + name
+		}
+		end := len(name) - len(rec.TLD) - 1
+		for i := 0; i < end; {
+			if !yield(name[i:]) {
+				return
+			}
+			next := strings.IndexByte(name[i:end], '.')
+			if next < 0 {
+				return
+			}
+			i += next + 1
+		}
+	}
+}
+
+// LabelsSeq yields the labels of the record from left to right: service
+// labels, subdomain labels, the name and then the labels of the suffix
+func (r *Record) LabelsSeq() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, label := range r.ServiceLabels {
+			if !yield(label) {
+				return
+			}
+		}
+		for _, part := range []string{r.Subdomain, r.Name, r.TLD} {
+			for part != "" {
+				label, rest, _ := strings.Cut(part, ".")
+				if !yield(label) {
+					return
+				}
+				part = rest
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelsSeq(t *testing.T) {
+	ex := newTestDomain(t)
+	for _, host := range []string{
+		"WwW.eXample.com",
+		"super.long.subdomain.hacking.us.com",
+		"example.co.uk",
+		"www.example.com:8080",
+	} {
+		levels, err := ex.LevelsE(host)
+		assert.NoError(t, err)
+		assert.Equal(t, levels, slices.Collect(ex.LevelsSeq(host)), host)
+	}
+	assert.Empty(t, slices.Collect(ex.LevelsSeq("naan.example")))
+
+	// stopping early must not yield further levels
+	var first []string
+	for level := range ex.LevelsSeq("a.b.c.example.com") {
+		first = append(first, level)
+		if len(first) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"a.b.c.example.com", "b.c.example.com"}, first)
+}
+
+func TestRecordLabelsSeq(t *testing.T) {
+	r := &Record{Subdomain: "a.b", Name: "example", TLD: "co.uk", ServiceLabels: []string{"_dmarc"}}
+	assert.Equal(t, []string{"_dmarc", "a", "b", "example", "co", "uk"}, slices.Collect(r.LabelsSeq()))
+	r = &Record{Name: "blog", TLD: "google"}
+	assert.Equal(t, []string{"blog", "google"}, slices.Collect(r.LabelsSeq()))
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// Labels returns the labels of the record from left to right: service
+// labels, subdomain labels, the name and then the labels of the suffix
+func (r *Record) Labels() []string {
+	return slices.Collect(r.LabelsSeq())
+}
+
+// Depth returns the number of labels left of the registrable domain, service
+// labels included: 0 for "example.co.uk", 2 for "a.b.example.co.uk"
+func (r *Record) Depth() int {
+	depth := len(r.ServiceLabels)
+	if r.Subdomain != "" {
+		depth += strings.Count(r.Subdomain, ".") + 1
+	}
+	return depth
+}
+
+// IsApex reports whether the record is a registrable domain itself, such as
+// "example.co.uk"
+func (r *Record) IsApex() bool {
+	return r.Depth() == 0
+}
+
+// HasSubdomain reports whether the record has labels left of its registrable
+// domain
+func (r *Record) HasSubdomain() bool {
+	return r.Depth() > 0
+}
+
+// dotVariants maps the ideographic and fullwidth full stops that IDNA
+// (RFC 3490 section 3.1) treats as label separators to "."
+var dotVariants = strings.NewReplacer("\u3002", ".", "\uff0e", ".", "\uff61", ".")
+
+// normalizeDots replaces the Unicode dot variants in host with "."
+func normalizeDots(host string) string {
+	if !strings.ContainsAny(host, "\u3002\uff0e\uff61") {
+		return host
+	}
+	return dotVariants.Replace(host)
+}
+
+// SplitLabels splits a hostname into labels at the same boundaries as Parse.
+// The ideographic and fullwidth dots "。", "．" and "｡" separate labels like
+// ".", and a single trailing dot, marking a fully qualified name, is dropped.
+// Unlike Parse, which rejects a backslash as an invalid character,
+// SplitLabels also reads names escaped in DNS master file style: an escaped
+// dot ("a\.b") stays inside its label and "\DDD" decimal escapes are
+// decoded.
+func SplitLabels(host string) []string {
+	host = strings.TrimSuffix(normalizeDots(host), ".")
+	if host == "" {
+		return nil
+	}
+	if !strings.ContainsRune(host, '\\') {
+		return strings.Split(host, ".")
+	}
+	var labels []string
+	var label strings.Builder
+	for i := 0; i < len(host); i++ {
+		c := host[i]
+		switch {
+		case c == '.':
+			labels = append(labels, label.String())
+			label.Reset()
+		case c == '\\' && i+3 < len(host) && isDigits(host[i+1:i+4]):
+			n := int(host[i+1]-'0')*100 + int(host[i+2]-'0')*10 + int(host[i+3]-'0')
+			if n > 255 {
+				label.WriteByte(c)
+				continue
+			}
+			label.WriteByte(byte(n))
+			i += 3
+		case c == '\\' && i+1 < len(host):
+			label.WriteByte(host[i+1])
+			i++
+		default:
+			label.WriteByte(c)
+		}
+	}
+	return append(labels, label.String())
+}
+
+// isDigits checks that s only holds ASCII digits
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitLabels(t *testing.T) {
+	tests := []struct {
+		i string
+		o []string
+	}{
+		{i: "www.example.com", o: []string{"www", "example", "com"}},
+		{i: "www.example.com.", o: []string{"www", "example", "com"}},
+		{i: "", o: nil},
+		{i: ".", o: nil},
+		{i: `a\.b.example.com`, o: []string{"a.b", "example", "com"}},
+		{i: `a\046b.example.com`, o: []string{"a.b", "example", "com"}},
+		{i: `a\\b.example.com`, o: []string{`a\b`, "example", "com"}},
+		{i: `a\999.com`, o: []string{`a\999`, "com"}},
+		{i: "a..com", o: []string{"a", "", "com"}},
+		{i: "www。example．co｡uk", o: []string{"www", "example", "co", "uk"}},
+	}
+	for _, ts := range tests {
+		assert.Equal(t, ts.o, SplitLabels(ts.i), ts.i)
+	}
+
+	// Parse does not read escapes
+	ex := newTestDomain(t)
+	_, err := ex.Parse(`a\.b.example.com`)
+	assert.Error(t, err)
+}
+
+func TestRecordLabels(t *testing.T) {
+	ex := newTestDomain(t)
+	r, err := ex.Parse("www.example.co.uk.")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "www", Name: "example", TLD: "co.uk"}, r)
+	assert.Equal(t, []string{"www", "example", "co", "uk"}, r.Labels())
+	assert.Equal(t, SplitLabels("www.example.co.uk."), r.Labels())
+}
+
+func TestRecordDepth(t *testing.T) {
+	tests := []struct {
+		i    Record
+		o    int
+		apex bool
+	}{
+		{i: Record{Name: "example", TLD: "co.uk"}, o: 0, apex: true},
+		{i: Record{Subdomain: "www", Name: "example", TLD: "co.uk"}, o: 1},
+		{i: Record{Subdomain: "a.b.c", Name: "example", TLD: "com"}, o: 3},
+		{i: Record{Name: "example", TLD: "com", ServiceLabels: []string{"_dmarc"}}, o: 1},
+	}
+	for _, ts := range tests {
+		assert.Equal(t, ts.o, ts.i.Depth(), ts.i.String())
+		assert.Equal(t, ts.apex, ts.i.IsApex(), ts.i.String())
+		assert.Equal(t, !ts.apex, ts.i.HasSubdomain(), ts.i.String())
+	}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotUnderParent is returned by LevelsUnder, wrapped with both names, when
+// the host is neither the parent nor one of its subdomains
+var ErrNotUnderParent = errors.New("host is not under the parent")
+
+// LevelsUnder returns the levels of host from the host itself down to
+// parent, which is the last level, such as every zone under a delegated
+// "internal.example.com" for "a.b.internal.example.com". Unlike LevelsE the
+// parent may lie on either side of the registrable domain, any ancestor of
+// host down to its public suffix can be given. The wildcard and service
+// labels of host are levels like any other, and labels compare without regard
+// to case, so WithPreserveCase keeps the casing of host in the levels.
+func (d *Domain) LevelsUnder(host, parent string) ([]string, error) {
+	rec, err := d.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+	var labels []string
+	if rec.Wildcard {
+		labels = append(labels, "*")
+	}
+	labels = append(labels, rec.ServiceLabels...)
+	if rec.Subdomain != "" {
+		labels = append(labels, strings.Split(rec.Subdomain, ".")...)
+	}
+	labels = append(labels, rec.Name)
+	labels = append(labels, SplitLabels(rec.TLD)...)
+	parentLabels := SplitLabels(foldCase(strings.TrimPrefix(parent, "."), d.opts.casePolicy))
+	top := len(labels) - len(parentLabels)
+	if len(parentLabels) == 0 || top < 0 || !equalLabels(labels[top:], parentLabels) {
+		return nil, fmt.Errorf("\"%s\" under \"%s\": %w", host, parent, ErrNotUnderParent)
+	}
+	levels := make([]string, 0, top+1)
+	for i := 0; i <= top; i++ {
+		levels = append(levels, strings.Join(labels[i:], "."))
+	}
+	return levels, nil
+}
+
+// equalLabels compares labels case-insensitively, treating the punycode and
+// Unicode forms of a label as equal
+func equalLabels(a, b []string) bool {
+	for i := range a {
+		if strings.EqualFold(a[i], b[i]) {
+			continue
+		}
+		x, errX := toASCII(strings.ToLower(a[i]))
+		y, errY := toASCII(strings.ToLower(b[i]))
+		if errX != nil || errY != nil || x != y {
+			return false
+		}
+	}
+	return true
+}
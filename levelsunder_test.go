@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelsUnder(t *testing.T) {
+	d, err := NewFromList(strings.NewReader("com\nuk\nco.uk\n公司.cn\ncn\n"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		host, parent string
+		levels       []string
+	}{
+		{"a.b.internal.example.com", "internal.example.com", []string{"a.b.internal.example.com", "b.internal.example.com", "internal.example.com"}},
+		{"a.b.internal.example.com:8443", "Internal.Example.COM.", []string{"a.b.internal.example.com", "b.internal.example.com", "internal.example.com"}},
+		{"internal.example.com", "internal.example.com", []string{"internal.example.com"}},
+		{"www.example.co.uk", "co.uk", []string{"www.example.co.uk", "example.co.uk", "co.uk"}},
+		{"www.example.co.uk", "example.co.uk", []string{"www.example.co.uk", "example.co.uk"}},
+		{"www.example.公司.cn", "example.xn--55qx5d.cn", []string{"www.example.公司.cn", "example.公司.cn"}},
+	}
+	for _, ts := range tests {
+		levels, err := d.LevelsUnder(ts.host, ts.parent)
+		assert.NoError(t, err, ts.host)
+		assert.Equal(t, ts.levels, levels, ts.host)
+	}
+
+	for _, parent := range []string{"other.example.com", "ternal.example.com", "a.b.internal.example.com.net", ""} {
+		_, err = d.LevelsUnder("a.b.internal.example.com", parent)
+		assert.True(t, errors.Is(err, ErrNotUnderParent), parent)
+	}
+	_, err = d.LevelsUnder("bad", "example.com")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrNotUnderParent))
+}
+
+func TestLevelsUnderPreserveCase(t *testing.T) {
+	d, err := NewFromList(strings.NewReader("com\n"), WithPreserveCase())
+	assert.NoError(t, err)
+	levels, err := d.LevelsUnder("WWW.Example.COM", "example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"WWW.Example.COM", "Example.COM"}, levels)
+	levels, err = d.LevelsUnder("www.example.com", "EXAMPLE.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"www.example.com", "example.com"}, levels)
+}
+
+func TestLevelsUnderServiceLabels(t *testing.T) {
+	d, err := NewFromList(strings.NewReader("com\n"), WithServiceLabels())
+	assert.NoError(t, err)
+	levels, err := d.LevelsUnder("_443._tcp.mail.example.com", "example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"_443._tcp.mail.example.com", "_tcp.mail.example.com", "mail.example.com", "example.com"}, levels)
+	levels, err = d.LevelsUnder("_dmarc.example.com", "_dmarc.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"_dmarc.example.com"}, levels)
+}
+
+func TestLevelsUnderWildcard(t *testing.T) {
+	d, err := NewFromList(strings.NewReader("com\n"))
+	assert.NoError(t, err)
+	levels, err := d.LevelsUnder("*.dev.example.com", "example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"*.dev.example.com", "dev.example.com", "example.com"}, levels)
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Default input limits of Parse. A hostname has at most 253 characters and
+// 127 labels, the length limit leaves room for a port and for the URLs
+// WithLenientInput reduces to their host.
+const (
+	DefaultMaxInputLength = 1024
+	DefaultMaxLabels      = 127
+)
+
+// ErrInputTooLarge is the error behind ErrCodeInputTooLarge. The Input of
+// such a ParseError is cut to the length limit so that it stays printable.
+var ErrInputTooLarge = errors.New("input too large")
+
+// WithInputLimits sets the longest input in bytes and the most labels Parse
+// accepts, anything larger fails with ErrInputTooLarge before it is split.
+// Zero removes a limit. The defaults are DefaultMaxInputLength and
+// DefaultMaxLabels.
+func WithInputLimits(maxLength, maxLabels int) Option {
+	return func(o *options) {
+		o.maxInputLength = maxLength
+		o.maxLabels = maxLabels
+	}
+}
+
+// checkInputLength enforces the input length limit
+func (d *Domain) checkInputLength(input string) error {
+	limit := d.opts.maxInputLength
+	if limit == 0 || len(input) <= limit {
+		return nil
+	}
+	// cut at a rune boundary so the error holds valid UTF-8
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(input[cut]) {
+		cut--
+	}
+	e := newInputError(input[:cut], ErrCodeInputTooLarge, fmt.Sprintf("input is %d bytes, more than the limit of %d", len(input), limit))
+	e.Err = ErrInputTooLarge
+	return e
+}
+
+// checkLabelCount enforces the label count limit without splitting host
+func (d *Domain) checkLabelCount(host string) error {
+	limit := d.opts.maxLabels
+	if limit == 0 {
+		return nil
+	}
+	if n := strings.Count(strings.TrimSuffix(host, "."), ".") + 1; n > limit {
+		e := newInputError(host, ErrCodeInputTooLarge, fmt.Sprintf("input has %d labels, more than the limit of %d", n, limit))
+		e.Err = ErrInputTooLarge
+		return e
+	}
+	return nil
+}
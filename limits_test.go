@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInputLimits(t *testing.T) {
+	ex := newTestDomain(t)
+	_, err := ex.Parse(strings.Repeat("a", 2<<20) + ".com")
+	if assert.IsType(t, &ParseError{}, err) {
+		pe := err.(*ParseError)
+		assert.Equal(t, ErrCodeInputTooLarge, pe.Code)
+		assert.Len(t, pe.Input, DefaultMaxInputLength)
+		assert.True(t, errors.Is(err, ErrInputTooLarge))
+	}
+	_, err = ex.Parse(strings.Repeat("a.", 200) + "com")
+	assert.True(t, errors.Is(err, ErrInputTooLarge))
+	assert.Contains(t, err.Error(), "input has 201 labels, more than the limit of 127")
+
+	small := newTestDomain(t, WithInputLimits(20, 3))
+	_, err = small.Parse("www.example.com.")
+	assert.NoError(t, err)
+	_, err = small.Parse("a.www.example.com")
+	assert.True(t, errors.Is(err, ErrInputTooLarge))
+	_, err = small.Parse("www.long-example.com")
+	assert.NoError(t, err)
+	_, err = small.Parse("www.longer-example.com")
+	assert.EqualError(t, err, `parse "www.longer-example.c": input is 22 bytes, more than the limit of 20`)
+
+	// the input is cut before a rune that crosses the limit
+	_, err = small.Parse("www.exampleeeeeeeeeé.com")
+	if assert.IsType(t, &ParseError{}, err) {
+		assert.Equal(t, "www.exampleeeeeeeee", err.(*ParseError).Input)
+	}
+	assert.True(t, utf8.ValidString(small.Validate(strings.Repeat("é", 30)+".com").Host))
+
+	unlimited := newTestDomain(t, WithInputLimits(0, 0))
+	_, err = unlimited.Parse(strings.Repeat("a.", 200) + "com")
+	assert.NoError(t, err)
+
+	o := newOptions([]Option{WithInputLimits(-1, 0)})
+	err = o.validate("/tmp/tld.cache")
+	if assert.IsType(t, &OptionsError{}, err) {
+		assert.Equal(t, []string{"input limits cannot be negative"}, err.(*OptionsError).Problems)
+	}
+}
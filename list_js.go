@@ -0,0 +1,8 @@
+//go:build js
+
+package domain
+
+// platformListURL is where the list is downloaded from by default. In a
+// browser net/http goes through fetch, which publicsuffix.org does not allow
+// cross origin, so the GitHub copy is used instead.
+const platformListURL = GitHubMirror
@@ -0,0 +1,6 @@
+//go:build !js
+
+package domain
+
+// platformListURL is where the list is downloaded from by default
+const platformListURL = DefaultListURL
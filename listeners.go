@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// rulesListener wraps a function given to OnRulesChanged, the pointer
+// identifies it for removal
+type rulesListener struct {
+	fn func(UpdateReport)
+}
+
+// OnRulesChanged registers fn to be called after every hot reload of the
+// rules, whether by Refresh, Reload, Watch, StartAutoRefresh, SetList or
+// ReplaceFrom, with a report of the rules added and removed. Loading the
+// same rules again still calls fn, with a report whose Changed is false.
+// Listeners run in registration order on the goroutine that reloaded the
+// rules, after the new rules are in place. Call the returned function to
+// remove the listener.
+func (d *Domain) OnRulesChanged(fn func(UpdateReport)) (remove func()) {
+	l := &rulesListener{fn: fn}
+	d.listenersMu.Lock()
+	d.listeners = append(d.listeners, l)
+	d.listenersMu.Unlock()
+	return func() {
+		d.listenersMu.Lock()
+		defer d.listenersMu.Unlock()
+		for i, other := range d.listeners {
+			if other == l {
+				d.listeners = append(d.listeners[:i:i], d.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// notifyRulesChanged calls the listeners with the difference between the
+// previous and current rules
+func (d *Domain) notifyRulesChanged(previous []Rule, previousVersion ListVersion, current []Rule, currentVersion ListVersion) {
+	d.listenersMu.Lock()
+	listeners := append([]*rulesListener(nil), d.listeners...)
+	d.listenersMu.Unlock()
+	if len(listeners) == 0 {
+		return
+	}
+	report := UpdateReport{Time: time.Now(), Previous: previousVersion, Current: currentVersion}
+	report.Added, report.Removed = diffRules(previous, current)
+	for _, l := range listeners {
+		l.fn(report)
+	}
+}
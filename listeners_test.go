@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnRulesChanged(t *testing.T) {
+	d, err := NewFromList(strings.NewReader("// VERSION: 1\ncom\norg\n"))
+	assert.NoError(t, err)
+
+	var first, second []UpdateReport
+	removeFirst := d.OnRulesChanged(func(r UpdateReport) { first = append(first, r) })
+	d.OnRulesChanged(func(r UpdateReport) { second = append(second, r) })
+
+	assert.NoError(t, d.SetList([]string{"com", "net"}))
+	if assert.Len(t, first, 1) {
+		assert.Equal(t, "1", first[0].Previous.Version)
+		assert.Equal(t, []string{"net"}, ruleTexts(first[0].Added))
+		assert.Equal(t, []string{"org"}, ruleTexts(first[0].Removed))
+	}
+
+	removeFirst()
+	removeFirst()
+	assert.NoError(t, d.ReplaceFrom(strings.NewReader("// VERSION: 3\ncom\nnet\n")))
+	assert.Len(t, first, 1)
+	if assert.Len(t, second, 2) {
+		assert.Equal(t, "3", second[1].Current.Version)
+		assert.False(t, second[1].Changed())
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+
+package domain
+
+import "os"
+
+// lockFile is a no-op on platforms without advisory file locking
+func lockFile(f *os.File, exclusive bool) error {
+	return nil
+}
+
+// unlockFile is a no-op on platforms without advisory file locking
+func unlockFile(f *os.File) error {
+	return nil
+}
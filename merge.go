@@ -0,0 +1,207 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PublicSuffixSource is the source name given to the downloaded public suffix list
+const PublicSuffixSource = "publicsuffix.org"
+
+// Source is a named list of suffix rules merged into a Domain with WithSource.
+//
+// When several sources provide the same rule the one with the highest Priority
+// is credited with it, ties go to the source given first (the public suffix
+// list always comes first with priority 0). Rules of different kinds that
+// cover the same name are resolved by kind regardless of priority: an
+// exception ("!a.b") beats an exact rule ("a.b"), and a wildcard ("*.b") beats
+// an exact rule it already covers ("a.b").
+type Source struct {
+	Name     string
+	Priority int
+	Rules    []string
+}
+
+// Resolution describes which source won a rule when sources were merged
+type Resolution struct {
+	// Rule is the rule kept after merging, it differs from the rule that was
+	// looked up when an exception or wildcard took precedence over it
+	Rule string
+	// Source is the name of the source credited with Rule
+	Source string
+	// Overridden lists the other sources that provided the looked up rule
+	Overridden []string
+}
+
+// Resolve reports which source won the given rule. It returns false if no
+// source provided the rule.
+func (d *Domain) Resolve(rule string) (Resolution, bool) {
+	rule = strings.ToLower(rule)
+	s := d.snapshot()
+	if s.resolutions == nil {
+		if !s.store.exists(rule) {
+			return Resolution{}, false
+		}
+		return Resolution{Rule: rule, Source: PublicSuffixSource}, true
+	}
+	res, ok := s.resolutions[rule]
+	return res, ok
+}
+
+// mergeSources combines the rules of all sources and returns the surviving
+// rules along with a resolution for every rule any source provided
+func mergeSources(sources []Source) ([]string, map[string]Resolution) {
+	type winner struct {
+		source   string
+		priority int
+		others   []string
+	}
+	winners := make(map[string]*winner)
+	for _, src := range sources {
+		for _, rule := range src.Rules {
+			rule = strings.ToLower(strings.TrimSpace(rule))
+			if rule == "" || strings.HasPrefix(rule, "//") {
+				continue
+			}
+			w, ok := winners[rule]
+			switch {
+			case !ok:
+				winners[rule] = &winner{source: src.Name, priority: src.Priority}
+			case w.source == src.Name:
+			case src.Priority > w.priority:
+				w.others = append(w.others, w.source)
+				w.source, w.priority = src.Name, src.Priority
+			default:
+				w.others = append(w.others, src.Name)
+			}
+		}
+	}
+
+	// kind precedence: exact rules lose to exceptions and covering wildcards
+	supersededBy := make(map[string]string)
+	for rule := range winners {
+		if strings.HasPrefix(rule, "!") || strings.HasPrefix(rule, "*.") {
+			continue
+		}
+		if _, ok := winners["!"+rule]; ok {
+			supersededBy[rule] = "!" + rule
+		} else if i := strings.IndexByte(rule, '.'); i >= 0 {
+			if _, ok := winners["*"+rule[i:]]; ok {
+				supersededBy[rule] = "*" + rule[i:]
+			}
+		}
+	}
+
+	rules := make([]string, 0, len(winners))
+	resolutions := make(map[string]Resolution, len(winners))
+	for rule, w := range winners {
+		res := Resolution{Rule: rule, Source: w.source, Overridden: w.others}
+		if by, ok := supersededBy[rule]; ok {
+			res.Rule = by
+			res.Source = winners[by].source
+			res.Overridden = nil
+			for _, name := range append([]string{w.source}, w.others...) {
+				if name != res.Source {
+					res.Overridden = append(res.Overridden, name)
+				}
+			}
+		} else {
+			rules = append(rules, rule)
+		}
+		resolutions[rule] = res
+	}
+	sort.Strings(rules)
+	return rules, resolutions
+}
+
+// creditBase replaces PublicSuffixSource, the name mergeSources knows the
+// loaded rules by, with the sources the rules themselves carry, which differ
+// for rules loaded from a SuffixProvider
+func creditBase(resolutions map[string]Resolution, base map[string]Rule) {
+	for rule, res := range resolutions {
+		if res.Source == PublicSuffixSource {
+			res.Source = base[res.Rule].Source
+		}
+		for i, name := range res.Overridden {
+			if name == PublicSuffixSource {
+				res.Overridden[i] = base[rule].Source
+			}
+		}
+		resolutions[rule] = res
+	}
+}
+
+// ruleResolutions returns the resolutions of rules that did not go through
+// mergeSources, or nil when every rule comes from the public suffix list
+func ruleResolutions(rules []Rule) map[string]Resolution {
+	for _, r := range rules {
+		if r.Source != PublicSuffixSource {
+			resolutions := make(map[string]Resolution, len(rules))
+			for _, r := range rules {
+				resolutions[r.String()] = Resolution{Rule: r.String(), Source: r.Source}
+			}
+			return resolutions
+		}
+	}
+	return nil
+}
+
+// mergedProviders is the SuffixProvider returned by Merge
+type mergedProviders []SuffixProvider
+
+// Merge layers the rules of several providers into one SuffixProvider, such
+// as the public suffix list, a company-internal zone list and a customer
+// specific override list, in that order. Later providers take precedence:
+// a rule provided more than once keeps the Section and Source of the last
+// provider that has it. Rules of different kinds are resolved as for Source, an
+// exception beats the exact rule and a wildcard beats an exact rule it
+// covers, whichever provider they come from. Load fails if any provider
+// fails.
+func Merge(providers ...SuffixProvider) SuffixProvider {
+	return mergedProviders(providers)
+}
+
+// Load loads every provider in order and merges their rules
+func (m mergedProviders) Load(ctx context.Context) ([]Rule, error) {
+	sources := make([]Source, len(m))
+	loaded := make([]map[string]Rule, len(m))
+	for i, p := range m {
+		rules, err := p.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("provider %d: %v", i+1, err)
+		}
+		// sources are named by index so providers sharing a source name
+		// still take precedence in order
+		sources[i] = Source{Name: strconv.Itoa(i), Priority: i, Rules: ruleTexts(rules)}
+		loaded[i] = make(map[string]Rule, len(rules))
+		for _, r := range rules {
+			loaded[i][strings.ToLower(strings.TrimSpace(r.String()))] = r
+		}
+	}
+	texts, resolutions := mergeSources(sources)
+	rules := make([]Rule, len(texts))
+	for i, text := range texts {
+		winner, _ := strconv.Atoi(resolutions[text].Source)
+		r := loaded[winner][text]
+		rules[i] = parseRule(text, r.Section, r.Source)
+	}
+	// keep each section together so the list has one block per section
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Section < rules[j].Section
+	})
+	return rules, nil
+}
+
+// NewFromLists creates a Domain from lists of rules written as in the suffix
+// list, "com", "*.ck" or "!www.ck", layered with Merge so later lists take
+// precedence over earlier ones
+func NewFromLists(lists ...[]string) (*Domain, error) {
+	providers := make([]SuffixProvider, len(lists))
+	for i, list := range lists {
+		providers[i] = StaticProvider(list)
+	}
+	return New("", WithProvider(Merge(providers...)))
+}
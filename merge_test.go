@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSources(t *testing.T) {
+	psl := "com\nco.uk\n*.ck\nexample.ck\nfoo.bar\n"
+	o := newOptions([]Option{
+		WithSource(Source{Name: "corp", Priority: 10, Rules: []string{"co.uk", "internal.corp", "!foo.bar"}}),
+		WithSource(Source{Name: "team", Priority: 5, Rules: []string{"internal.corp", "dev.internal.corp"}}),
+	})
+	d := newDomain("", strings.NewReader(psl), &o)
+
+	tests := []struct {
+		rule string
+		res  Resolution
+		ok   bool
+	}{
+		{rule: "com", res: Resolution{Rule: "com", Source: PublicSuffixSource}, ok: true},
+		{rule: "co.uk", res: Resolution{Rule: "co.uk", Source: "corp", Overridden: []string{PublicSuffixSource}}, ok: true},
+		{rule: "internal.corp", res: Resolution{Rule: "internal.corp", Source: "corp", Overridden: []string{"team"}}, ok: true},
+		{rule: "dev.internal.corp", res: Resolution{Rule: "dev.internal.corp", Source: "team"}, ok: true},
+		{rule: "example.ck", res: Resolution{Rule: "*.ck", Source: PublicSuffixSource}, ok: true},
+		{rule: "foo.bar", res: Resolution{Rule: "!foo.bar", Source: "corp", Overridden: []string{PublicSuffixSource}}, ok: true},
+		{rule: "missing", ok: false},
+	}
+	for _, ts := range tests {
+		res, ok := d.Resolve(ts.rule)
+		assert.Equal(t, ts.ok, ok, ts.rule)
+		assert.Equal(t, ts.res, res, "These should be equal!")
+	}
+	assert.False(t, d.snapshot().store.exists("foo.bar"))
+	assert.False(t, d.snapshot().store.exists("example.ck"))
+	assert.True(t, d.snapshot().store.exists("!foo.bar"))
+}
+
+func TestSourceOptionsValidate(t *testing.T) {
+	o := newOptions([]Option{
+		WithSource(Source{Name: "corp"}),
+		WithSource(Source{Name: "corp"}),
+		WithSource(Source{Name: PublicSuffixSource}),
+		WithSource(Source{}),
+	})
+	err := o.validate("/tmp/tld.cache")
+	if assert.IsType(t, &OptionsError{}, err) {
+		assert.Equal(t, []string{
+			`source name "corp" is used more than once`,
+			`source name "publicsuffix.org" is used more than once`,
+			"sources must be named",
+		}, err.(*OptionsError).Problems)
+	}
+}
+
+func TestMergeProviders(t *testing.T) {
+	public := ProviderFunc(func(ctx context.Context) ([]Rule, error) {
+		return []Rule{
+			parseRule("com", SectionICANN, PublicSuffixSource),
+			parseRule("uk", SectionICANN, PublicSuffixSource),
+			parseRule("co.uk", SectionICANN, PublicSuffixSource),
+			parseRule("ck", SectionICANN, PublicSuffixSource),
+			parseRule("*.ck", SectionICANN, PublicSuffixSource),
+		}, nil
+	})
+	internal := ProviderFunc(func(ctx context.Context) ([]Rule, error) {
+		return []Rule{
+			parseRule("corp", SectionPrivate, "corp"),
+			parseRule("dev.corp", SectionPrivate, "corp"),
+		}, nil
+	})
+	override := StaticProvider{"!www.ck", "co.uk", "dev.corp"}
+
+	rules, err := Merge(public, internal, override).Load(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"!www.ck", "co.uk", "dev.corp", "*.ck", "ck", "com", "uk", "corp"}, ruleTexts(rules))
+	assert.Equal(t, SectionUnknown, rules[2].Section)
+	assert.Equal(t, SectionPrivate, rules[7].Section)
+
+	d, err := New("", WithProvider(Merge(public, internal, override)))
+	assert.NoError(t, err)
+	r, err := d.Parse("www.ck")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Name: "www", TLD: "ck"}, r)
+	r, err = d.Parse("api.team.dev.corp")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "api", Name: "team", TLD: "dev.corp"}, r)
+
+	// rules keep the source of the provider that won them
+	tests := []struct {
+		rule string
+		res  Resolution
+	}{
+		{rule: "com", res: Resolution{Rule: "com", Source: PublicSuffixSource}},
+		{rule: "corp", res: Resolution{Rule: "corp", Source: "corp"}},
+		{rule: "dev.corp", res: Resolution{Rule: "dev.corp", Source: StaticSource}},
+		{rule: "!www.ck", res: Resolution{Rule: "!www.ck", Source: StaticSource}},
+	}
+	for _, ts := range tests {
+		res, ok := d.Resolve(ts.rule)
+		assert.True(t, ok, ts.rule)
+		assert.Equal(t, ts.res, res, ts.rule)
+	}
+	d, err = New("", WithProvider(internal), WithSource(Source{Name: "team", Priority: 1, Rules: []string{"dev.corp"}}))
+	assert.NoError(t, err)
+	res, _ := d.Resolve("corp")
+	assert.Equal(t, Resolution{Rule: "corp", Source: "corp"}, res)
+	res, _ = d.Resolve("dev.corp")
+	assert.Equal(t, Resolution{Rule: "dev.corp", Source: "team", Overridden: []string{"corp"}}, res)
+
+	failing := ProviderFunc(func(ctx context.Context) ([]Rule, error) {
+		return nil, errors.New("unreachable")
+	})
+	_, err = Merge(public, failing).Load(context.Background())
+	assert.EqualError(t, err, "provider 2: unreachable")
+}
+
+func TestNewFromLists(t *testing.T) {
+	d, err := NewFromLists([]string{"com", "ck", "*.ck"}, []string{"!www.ck", "internal.com"})
+	assert.NoError(t, err)
+	r, err := d.Parse("a.www.ck")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "a", Name: "www", TLD: "ck"}, r)
+	r, err = d.Parse("api.internal.com")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Name: "api", TLD: "internal.com"}, r)
+}
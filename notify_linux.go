@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// notifyCache calls changed whenever cacheFile is written or replaced. It
+// watches the parent directory with inotify, since an atomic replace gives
+// the cache a new inode that a watch on the file itself would miss.
+func notifyCache(cacheFile string, changed func()) (stop func(), err error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	dir, name := filepath.Split(cacheFile)
+	if dir == "" {
+		dir = "."
+	}
+	mask := uint32(syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO | syscall.IN_CREATE)
+	if _, err := syscall.InotifyAddWatch(fd, dir, mask); err != nil {
+		syscall.Close(fd)
+		return nil, os.NewSyscallError("inotify_add_watch", err)
+	}
+	// a non-blocking fd is handed to the runtime poller, so Close unblocks Read
+	f := os.NewFile(uintptr(fd), "inotify")
+	go func() {
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				return
+			}
+			if inotifyNamed(buf[:n], name) {
+				changed()
+			}
+		}
+	}()
+	return func() { f.Close() }, nil
+}
+
+// inotifyNamed reports whether any event in buf concerns the file name
+func inotifyNamed(buf []byte, name string) bool {
+	for len(buf) >= syscall.SizeofInotifyEvent {
+		ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[0]))
+		end := syscall.SizeofInotifyEvent + int(ev.Len)
+		if end > len(buf) {
+			return false
+		}
+		raw := buf[syscall.SizeofInotifyEvent:end]
+		for i, c := range raw {
+			if c == 0 {
+				raw = raw[:i]
+				break
+			}
+		}
+		if string(raw) == name {
+			return true
+		}
+		buf = buf[end:]
+	}
+	return false
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package domain
+
+import "errors"
+
+// notifyCache is not implemented without inotify, callers fall back to polling
+func notifyCache(cacheFile string, changed func()) (stop func(), err error) {
+	return nil, errors.New("file notifications are not supported on this platform")
+}
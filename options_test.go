@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		cache    string
+		opts     []Option
+		problems []string
+	}{
+		{cache: "/tmp/tld.cache", opts: nil, problems: nil},
+		{cache: "/tmp/tld.cache", opts: []Option{WithOffline()}, problems: nil},
+		{cache: "", opts: []Option{WithInMemory()}, problems: nil},
+		{cache: "", opts: nil, problems: []string{
+			"a cache file is required unless in-memory mode, a cache backend or a suffix provider is used",
+		}},
+		{cache: "/tmp/tld.cache", opts: []Option{WithOffline(), WithRefreshInterval(time.Hour)}, problems: []string{
+			"offline mode cannot be combined with a refresh interval",
+		}},
+		{cache: "", opts: []Option{WithInMemory(), WithAutoReload(nil)}, problems: []string{
+			"in-memory mode cannot be combined with auto reload",
+		}},
+		{cache: "/tmp/tld.cache", opts: []Option{WithInMemory(), WithOffline(), WithRefreshInterval(-time.Hour)}, problems: []string{
+			"refresh interval cannot be negative",
+			"offline mode cannot be combined with in-memory mode",
+			"in-memory mode cannot be combined with a cache file",
+		}},
+	}
+	for _, ts := range tests {
+		o := newOptions(ts.opts)
+		err := o.validate(ts.cache)
+		if ts.problems == nil {
+			assert.NoError(t, err)
+			continue
+		}
+		if assert.IsType(t, &OptionsError{}, err) {
+			assert.Equal(t, ts.problems, err.(*OptionsError).Problems, "These should be equal!")
+		}
+	}
+}
+
+func TestNewRejectsInvalidOptions(t *testing.T) {
+	d, err := New("", WithOffline())
+	assert.Nil(t, d)
+	assert.IsType(t, &OptionsError{}, err)
+}
+
+func TestDownloadOptionsValidate(t *testing.T) {
+	o := newOptions([]Option{WithRetries(-1, -time.Second), WithListURL("file:///tmp/list.dat"), WithMirrors(GitHubMirror, "ftp://example.com/list", "not a url")})
+	err := o.validate("/tmp/tld.cache")
+	if assert.IsType(t, &OptionsError{}, err) {
+		assert.Equal(t, []string{
+			"retries cannot be negative",
+			"retry backoff cannot be negative",
+			`list URL "file:///tmp/list.dat" is not an http or https URL`,
+			`mirror "ftp://example.com/list" is not an http or https URL`,
+			`mirror "not a url" is not an http or https URL`,
+		}, err.(*OptionsError).Problems)
+	}
+}
+
+func TestProxyOptionsValidate(t *testing.T) {
+	o := newOptions([]Option{WithProxy("proxy:3128"), WithTransport(http.DefaultTransport)})
+	err := o.validate("/tmp/tld.cache")
+	if assert.IsType(t, &OptionsError{}, err) {
+		assert.Equal(t, []string{
+			`proxy "proxy:3128" is not a valid URL`,
+			"a proxy cannot be combined with a custom transport",
+		}, err.(*OptionsError).Problems)
+	}
+}
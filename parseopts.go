@@ -0,0 +1,55 @@
+package domain
+
+import "strings"
+
+// ParseOption changes how a single Parse call splits its input, leaving the
+// shared Domain untouched, so one Domain can serve callers with different
+// suffix boundaries such as the tenants of a service
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	extra          mapRules
+	withoutPrivate bool
+}
+
+// WithExtraSuffixes adds suffix rules for this call, written as in the
+// suffix list ("internal.acme", "*.dev.acme" or "!www.dev.acme"). Extra
+// rules win over WithoutPrivate.
+func WithExtraSuffixes(rules ...string) ParseOption {
+	return func(po *parseOptions) {
+		if po.extra == nil {
+			po.extra = make(mapRules, len(rules))
+		}
+		var clean []string
+		for _, rule := range rules {
+			rule = strings.ToLower(strings.Trim(strings.TrimSpace(rule), "."))
+			if checkRule(rule) == nil {
+				clean = append(clean, rule)
+			}
+		}
+		for _, rule := range withAlternateForms(clean) {
+			po.extra.add(rule)
+		}
+	}
+}
+
+// WithoutPrivate ignores the rules of the private section of the list for
+// this call, so "foo.github.io" parses with "io" as its TLD
+func WithoutPrivate() ParseOption {
+	return func(po *parseOptions) {
+		po.withoutPrivate = true
+	}
+}
+
+// newParseOptions applies opts, it returns nil when there are none so the
+// common case does not allocate
+func newParseOptions(opts []ParseOption) *parseOptions {
+	if len(opts) == 0 {
+		return nil
+	}
+	po := &parseOptions{}
+	for _, opt := range opts {
+		opt(po)
+	}
+	return po
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOptions(t *testing.T) {
+	list := "// ===BEGIN ICANN DOMAINS===\ncom\nio\nacme\n// ===END ICANN DOMAINS===\n// ===BEGIN PRIVATE DOMAINS===\ngithub.io\n// ===END PRIVATE DOMAINS===\n"
+	d, err := NewFromList(strings.NewReader(list))
+	assert.NoError(t, err)
+	tests := []struct {
+		i    string
+		opts []ParseOption
+		o    *Record
+	}{
+		{i: "db.team.internal.acme", o: &Record{Subdomain: "db.team", Name: "internal", TLD: "acme"}},
+		{i: "db.team.internal.acme", opts: []ParseOption{WithExtraSuffixes(".Internal.ACME.")}, o: &Record{Subdomain: "db", Name: "team", TLD: "internal.acme"}},
+		{i: "a.b.dev.acme", opts: []ParseOption{WithExtraSuffixes("*.dev.acme", "!www.dev.acme", "bad..rule")}, o: &Record{Name: "a", TLD: "b.dev.acme"}},
+		{i: "x.www.dev.acme", opts: []ParseOption{WithExtraSuffixes("*.dev.acme", "!www.dev.acme")}, o: &Record{Subdomain: "x", Name: "www", TLD: "dev.acme"}},
+		{i: "foo.github.io", o: &Record{Name: "foo", TLD: "github.io"}},
+		{i: "foo.github.io", opts: []ParseOption{WithoutPrivate()}, o: &Record{Subdomain: "foo", Name: "github", TLD: "io"}},
+		{i: "foo.github.io", opts: []ParseOption{WithoutPrivate(), WithExtraSuffixes("github.io")}, o: &Record{Name: "foo", TLD: "github.io"}},
+	}
+	for _, ts := range tests {
+		r, err := d.Parse(ts.i, ts.opts...)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+	}
+	// per-call rules do not leak into the shared Domain
+	r, err := d.Parse("db.team.internal.acme")
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", r.TLD)
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// Warning describes a problem ParseLenient worked around, or a warning of
+// the parsed record
+type Warning struct {
+	// Code is the kind of parse error that was worked around, zero for
+	// warnings Parse itself adds to Record.Warnings
+	Code    ParseErrorCode
+	Message string
+}
+
+// String returns the message of the warning
+func (w Warning) String() string {
+	return w.Message
+}
+
+// maxRepairs bounds the parse attempts of ParseLenient, every repair fixes
+// one kind of problem
+const maxRepairs = 4
+
+// trailingPort matches a ":port" suffix kept while removing bad characters
+var trailingPort = regexp.MustCompile(`:[0-9]{1,5}$`)
+
+// ParseLenient returns the best split it can make of host instead of failing,
+// along with warnings for every problem it worked around, so messy data can
+// be parsed in a single pass. Defanging and URL parts are removed as with
+// WithLenientInput, characters that cannot appear in a hostname, empty labels
+// and invalid ports are dropped, a name without a known suffix gets its last
+// label as TLD, a single label becomes the Name, a public suffix alone the
+// TLD, and an IP address is returned in Record.IP. A leading "*." is kept
+// in Record.Wildcard, and every attempt is a call to Parse. The record is nil
+// only if nothing could be made of host, such as input over the size limits
+// or a rejected special-use name.
+func (d *Domain) ParseLenient(host string) (*Record, []Warning) {
+	var warnings []Warning
+	h := lenientHost(strings.TrimSpace(host))
+	if h != host {
+		warnings = append(warnings, Warning{Message: "removed defanging, URL parts or surrounding space"})
+	}
+	rec, err := d.Parse(h)
+	for attempt := 0; err != nil && attempt < maxRepairs; attempt++ {
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			break
+		}
+		var fixed string
+		var msg string
+		switch pe.Code {
+		case ErrCodeInvalidCharacter:
+			fixed, msg = removeBadChars(h), "removed characters that cannot appear in a hostname"
+		case ErrCodeEmptyLabel:
+			fixed, msg = dropEmptyLabels(h), "dropped empty labels"
+		case ErrCodeInvalidPort:
+			if i := strings.LastIndexByte(h, ':'); i > 0 {
+				fixed, msg = h[:i], fmt.Sprintf("dropped invalid port: %s", pe.msg)
+			}
+		}
+		if fixed == "" {
+			rec, msg = partialRecord(h, pe)
+			if rec != nil {
+				return rec, append(warnings, Warning{Code: pe.Code, Message: msg})
+			}
+			break
+		}
+		warnings = append(warnings, Warning{Code: pe.Code, Message: msg})
+		h = fixed
+		rec, err = d.Parse(h)
+	}
+	if err != nil {
+		var pe *ParseError
+		if errors.As(err, &pe) {
+			return nil, append(warnings, Warning{Code: pe.Code, Message: pe.msg})
+		}
+		return nil, append(warnings, Warning{Message: err.Error()})
+	}
+	for _, w := range rec.Warnings {
+		warnings = append(warnings, Warning{Message: w})
+	}
+	return rec, warnings
+}
+
+// partialRecord builds the record ParseLenient returns for errors that
+// cannot be repaired by editing the input, along with the warning message
+func partialRecord(h string, pe *ParseError) (*Record, string) {
+	if pe.Code == ErrCodeIPAddress {
+		ip, port := ipLiteral(h)
+		if ip == nil {
+			return nil, ""
+		}
+		return &Record{IP: ip, Port: port}, "IP address instead of a domain name"
+	}
+	host, port, err := splitPort(h)
+	if err != nil {
+		host, port = h, ""
+	}
+	wildcard := strings.HasPrefix(host, "*.")
+	name := strings.TrimSuffix(pe.Input, ".")
+	switch pe.Code {
+	case ErrCodeUnknownTLD, ErrCodeNotDelegated, ErrCodeUnlistedSuffix:
+		labels := SplitLabels(name)
+		if len(labels) < 2 {
+			return nil, ""
+		}
+		tld := labels[len(labels)-1]
+		rec := &Record{Subdomain: strings.Join(labels[:len(labels)-2], "."), Name: labels[len(labels)-2], TLD: tld, Port: port, Wildcard: wildcard}
+		return rec, fmt.Sprintf("top level domain \"%s\" is not in the suffix list: %s", tld, pe.msg)
+	case ErrCodeNoDot:
+		return &Record{Name: name, Port: port, Wildcard: wildcard}, "single label name without a top level domain"
+	case ErrCodeMissingName:
+		return &Record{TLD: name, Port: port, Wildcard: wildcard}, "name is a public suffix without a domain name"
+	}
+	return nil, ""
+}
+
+// ipLiteral returns the address and port of an IP literal input such as
+// "192.0.2.1", "192.0.2.1:80" or "[2001:db8::1]:443", or nil for a name
+func ipLiteral(h string) (net.IP, string) {
+	if host, port, err := net.SplitHostPort(h); err == nil {
+		if ip := net.ParseIP(host); ip != nil {
+			return ip, port
+		}
+		return nil, ""
+	}
+	return net.ParseIP(strings.Trim(h, "[]")), ""
+}
+
+// removeBadChars removes the characters validator rejects from h, keeping a
+// trailing port
+func removeBadChars(h string) string {
+	port := trailingPort.FindString(h)
+	h = strings.Map(func(r rune) rune {
+		for _, c := range badChars {
+			if r == c {
+				return -1
+			}
+		}
+		return r
+	}, h[:len(h)-len(port)])
+	return h + port
+}
+
+// dropEmptyLabels removes empty labels and a trailing dot from h
+func dropEmptyLabels(h string) string {
+	var labels []string
+	for _, label := range strings.Split(strings.TrimSuffix(h, "."), ".") {
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return strings.Join(labels, ".")
+}
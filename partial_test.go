@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLenient(t *testing.T) {
+	tests := []struct {
+		i        string
+		o        *Record
+		warnings []Warning
+	}{
+		{i: "www.example.com", o: &Record{Subdomain: "www", Name: "example", TLD: "com"}},
+		{i: " hxxps://www.example[.]com/login ", o: &Record{Subdomain: "www", Name: "example", TLD: "com"}, warnings: []Warning{
+			{Message: "removed defanging, URL parts or surrounding space"},
+		}},
+		{i: "ww w..exa(mple).com:8080", o: &Record{Subdomain: "www", Name: "example", TLD: "com", Port: "8080"}, warnings: []Warning{
+			{Code: ErrCodeInvalidCharacter, Message: "removed characters that cannot appear in a hostname"},
+			{Code: ErrCodeEmptyLabel, Message: "dropped empty labels"},
+		}},
+		{i: "example.com:http", o: &Record{Name: "example", TLD: "com"}, warnings: []Warning{
+			{Code: ErrCodeInvalidPort, Message: `dropped invalid port: invalid port "http"`},
+		}},
+		{i: "db.payments.corp", o: &Record{Subdomain: "db", Name: "payments", TLD: "corp"}, warnings: []Warning{
+			{Code: ErrCodeUnknownTLD, Message: `top level domain "corp" is not in the suffix list: top level domain does not exist`},
+		}},
+		{i: "LocalHost:8080", o: &Record{Name: "localhost", Port: "8080"}, warnings: []Warning{
+			{Code: ErrCodeNoDot, Message: "single label name without a top level domain"},
+		}},
+		{i: "co.uk", o: &Record{TLD: "co.uk"}, warnings: []Warning{
+			{Code: ErrCodeMissingName, Message: "name is a public suffix without a domain name"},
+		}},
+		{i: "[2001:db8::1]:443", o: &Record{IP: net.ParseIP("2001:db8::1"), Port: "443"}, warnings: []Warning{
+			{Code: ErrCodeIPAddress, Message: "IP address instead of a domain name"},
+		}},
+		{i: "1.2.3.4", o: &Record{IP: net.ParseIP("1.2.3.4")}, warnings: []Warning{
+			{Code: ErrCodeIPAddress, Message: "IP address instead of a domain name"},
+		}},
+		{i: "192.0.2.1:80", o: &Record{IP: net.ParseIP("192.0.2.1"), Port: "80"}, warnings: []Warning{
+			{Code: ErrCodeIPAddress, Message: "IP address instead of a domain name"},
+		}},
+		{i: "*.foo.internal", o: &Record{Name: "foo", TLD: "internal", Wildcard: true}, warnings: []Warning{
+			{Code: ErrCodeUnknownTLD, Message: `top level domain "internal" is not in the suffix list: top level domain does not exist`},
+		}},
+		{i: "*.co.uk:443", o: &Record{TLD: "co.uk", Port: "443", Wildcard: true}, warnings: []Warning{
+			{Code: ErrCodeMissingName, Message: "name is a public suffix without a domain name"},
+		}},
+		{i: "{}", o: nil, warnings: []Warning{
+			{Code: ErrCodeInvalidCharacter, Message: `domain name cannot contain "}"`},
+		}},
+	}
+	ex := newTestDomain(t)
+	for _, ts := range tests {
+		r, warnings := ex.ParseLenient(ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+		assert.Equal(t, ts.warnings, warnings, ts.i)
+	}
+
+	// every attempt goes through Parse
+	before := ex.Stats().Parses
+	ex.ParseLenient("ww w..example.com")
+	assert.Equal(t, before+3, ex.Stats().Parses)
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MatchesPattern reports whether host matches a certificate name such as
+// "*.example.com", following the wildcard rules of RFC 6125: a wildcard must
+// be the whole leftmost label and stands for exactly one non-empty label, so
+// "*.example.com" matches "a.example.com" but neither "a.b.example.com" nor
+// "example.com". Patterns whose wildcard would cover a public suffix, such as
+// "*.co.uk" or "*.com", are rejected with an error. Names are compared
+// case-insensitively and in their punycode form.
+func (d *Domain) MatchesPattern(pattern, host string) (bool, error) {
+	pattern, err := patternName(pattern)
+	if err != nil {
+		return false, fmt.Errorf("pattern \"%s\": %v", pattern, err)
+	}
+	host, err = patternName(host)
+	if err != nil {
+		return false, fmt.Errorf("pattern: host \"%s\": %v", host, err)
+	}
+	if strings.Contains(strings.TrimPrefix(pattern, "*."), "*") {
+		return false, fmt.Errorf("pattern \"%s\": a wildcard must be the whole leftmost label", pattern)
+	}
+	if !strings.HasPrefix(pattern, "*.") {
+		return pattern == host, nil
+	}
+
+	base := pattern[2:]
+	labels := strings.Split(base, ".")
+	start, ok := d.suffixStart(labels)
+	if !ok {
+		return false, fmt.Errorf("pattern \"%s\": top level domain does not exist", pattern)
+	}
+	if start == 0 {
+		return false, fmt.Errorf("pattern \"%s\": wildcard covers the public suffix \"%s\"", pattern, base)
+	}
+	i := strings.IndexByte(host, '.')
+	return i > 0 && host[i+1:] == base, nil
+}
+
+// patternName lowercases a name, drops a trailing dot and converts it to
+// punycode, leaving a "*" label in place
+func patternName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if name == "" {
+		return "", fmt.Errorf("empty name")
+	}
+	rest, prefix := name, ""
+	if strings.HasPrefix(name, "*.") {
+		rest, prefix = name[2:], "*."
+	}
+	ascii, err := toASCII(rest)
+	if err != nil {
+		return name, err
+	}
+	for _, label := range strings.Split(ascii, ".") {
+		if label == "" {
+			return name, fmt.Errorf("name cannot contain an empty label")
+		}
+	}
+	return prefix + ascii, nil
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchesPattern(t *testing.T) {
+	tests := []struct {
+		pattern, host string
+		o             bool
+		err           bool
+	}{
+		{pattern: "*.example.com", host: "a.example.com", o: true},
+		{pattern: "*.EXAMPLE.com.", host: "A.example.com", o: true},
+		{pattern: "*.example.com", host: "a.b.example.com", o: false},
+		{pattern: "*.example.com", host: "example.com", o: false},
+		{pattern: "www.example.com", host: "WWW.example.com", o: true},
+		{pattern: "www.example.com", host: "api.example.com", o: false},
+		{pattern: "*.bücher.de", host: "www.xn--bcher-kva.de", o: true},
+		{pattern: "*.co.uk", host: "example.co.uk", err: true},
+		{pattern: "*.com", host: "example.com", err: true},
+		{pattern: "a.*.example.com", host: "a.b.example.com", err: true},
+		{pattern: "f*o.example.com", host: "foo.example.com", err: true},
+		{pattern: "*.example.nonexist", host: "a.example.nonexist", err: true},
+	}
+	ex := newTestDomain(t)
+	for _, ts := range tests {
+		ok, err := ex.MatchesPattern(ts.pattern, ts.host)
+		if ts.err {
+			assert.Error(t, err, ts.pattern)
+			continue
+		}
+		assert.NoError(t, err, ts.pattern)
+		assert.Equal(t, ts.o, ok, ts.pattern+" "+ts.host)
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuffixProvider(t *testing.T) {
+	d, err := New("", WithProvider(StaticProvider{"com", "*.ck", "!www.ck"}))
+	assert.NoError(t, err)
+	r, err := d.Parse("a.b.example.ck")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "a", Name: "b", TLD: "example.ck"}, r)
+	r, err = d.Parse("www.ck")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Name: "www", TLD: "ck"}, r)
+
+	loads := 0
+	p := ProviderFunc(func(ctx context.Context) ([]Rule, error) {
+		loads++
+		if loads > 1 {
+			return nil, errors.New("unavailable")
+		}
+		return []Rule{
+			{Suffix: "com", Section: SectionICANN},
+			{Suffix: "github.io", Section: SectionPrivate},
+		}, nil
+	})
+	d, err = New("", WithProvider(p))
+	assert.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Suffix: "com", Section: SectionICANN, Source: PublicSuffixSource},
+		{Suffix: "github.io", Section: SectionPrivate, Source: PublicSuffixSource},
+	}, d.Rules())
+	assert.Error(t, d.Refresh())
+	_, err = d.Parse("user.github.io")
+	assert.NoError(t, err)
+
+	_, err = New("/tmp/tld.cache", WithProvider(p))
+	assert.IsType(t, &OptionsError{}, err)
+}
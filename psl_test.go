@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pslTest is one checkPublicSuffix line of the official test file
+type pslTest struct {
+	section     string
+	input       string
+	registrable string
+	null        bool
+}
+
+var checkPublicSuffix = regexp.MustCompile(`^checkPublicSuffix\((null|'[^']*'), (null|'[^']*')\);`)
+
+// readPSLTests loads testdata/test_psl.txt, from
+// https://github.com/publicsuffix/list/blob/master/tests/test_psl.txt
+func readPSLTests(t *testing.T) []pslTest {
+	f, err := os.Open("testdata/test_psl.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var tests []pslTest
+	var section string
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		line := strings.TrimSpace(scan.Text())
+		if strings.HasPrefix(line, "//") {
+			section = strings.TrimSpace(strings.TrimPrefix(line, "//"))
+			continue
+		}
+		m := checkPublicSuffix.FindStringSubmatch(line)
+		if m == nil || m[1] == "null" {
+			continue
+		}
+		tests = append(tests, pslTest{
+			section:     section,
+			input:       strings.Trim(m[1], "'"),
+			registrable: strings.Trim(m[2], "'"),
+			null:        m[2] == "null",
+		})
+	}
+	return tests
+}
+
+func TestPublicSuffixList(t *testing.T) {
+	ex := newPSLDomain(t)
+	for _, ts := range readPSLTests(t) {
+		r, err := ex.Parse(ts.input)
+		// the implicit "*" rule for unlisted TLDs is not applied by default
+		if ts.null || ts.section == "Unlisted TLD." {
+			assert.Error(t, err, "%s: %s", ts.section, ts.input)
+			continue
+		}
+		if assert.NoError(t, err, "%s: %s", ts.section, ts.input) {
+			assert.Equal(t, ts.registrable, r.Name+"."+r.TLD, "%s: %s", ts.section, ts.input)
+		}
+	}
+}
+
+func TestPrevailingRule(t *testing.T) {
+	// "b.c" is listed but "c" is not, the longest match must still win
+	d := newDomain("", strings.NewReader("b.c\n*.ck\n!www.ck\n"), &options{})
+	tests := []struct {
+		i string
+		o *Record
+	}{
+		{i: "a.b.c", o: &Record{Name: "a", TLD: "b.c"}},
+		{i: "x.a.b.c", o: &Record{Subdomain: "x", Name: "a", TLD: "b.c"}},
+		{i: "b.c", o: nil},
+		{i: "x.c", o: nil},
+		{i: "a.b.test.ck", o: &Record{Subdomain: "a", Name: "b", TLD: "test.ck"}},
+		{i: "www.www.ck", o: &Record{Subdomain: "www", Name: "www", TLD: "ck"}},
+		{i: "test.ck", o: nil},
+		{i: ".example.b.c", o: nil},
+	}
+	for _, ts := range tests {
+		r, _ := d.Parse(ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PublicSuffix returns the public suffix of domain with the semantics of
+// golang.org/x/net/publicsuffix: the input is not validated or lowercased,
+// a name no rule matches falls back to its last label, and icann reports
+// whether the prevailing rule comes from the ICANN section of the list.
+// Rules of extra sources and of caches written without section markers
+// count as ICANN rules.
+func (d *Domain) PublicSuffix(domain string) (publicSuffix string, icann bool) {
+	labels := strings.Split(domain, ".")
+	start, rule, found := d.matchRule(labels, nil)
+	if !found {
+		return labels[len(labels)-1], false
+	}
+	return strings.Join(labels[start:], "."), !d.isPrivate(rule)
+}
+
+// isPrivate reports whether rule comes from the private section of the list
+func (d *Domain) isPrivate(rule string) bool {
+	_, ok := d.snapshot().private[rule]
+	return ok
+}
+
+// EffectiveTLDPlusOne returns the public suffix of domain plus one more
+// label, with the semantics and error messages of
+// golang.org/x/net/publicsuffix
+func (d *Domain) EffectiveTLDPlusOne(domain string) (string, error) {
+	if strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") || strings.Contains(domain, "..") {
+		return "", fmt.Errorf("publicsuffix: empty label in domain %q", domain)
+	}
+	suffix, _ := d.PublicSuffix(domain)
+	if len(domain) <= len(suffix) {
+		return "", fmt.Errorf("publicsuffix: cannot derive eTLD+1 for domain %q", domain)
+	}
+	i := len(domain) - len(suffix) - 1
+	if domain[i] != '.' {
+		return "", fmt.Errorf("publicsuffix: invalid public suffix %q for domain %q", suffix, domain)
+	}
+	return domain[1+strings.LastIndex(domain[:i], "."):], nil
+}
@@ -0,0 +1,66 @@
+// Package publicsuffix is a drop-in replacement for the functions of
+// golang.org/x/net/publicsuffix, backed by a domain.Domain so the list can
+// be refreshed at run time instead of being compiled into the binary.
+//
+// Call Use once at start up, then switch the import path:
+//
+//	d, err := domain.New("/tmp/tld.cache")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	publicsuffix.Use(d)
+//	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+//
+// Until Use is called the functions answer from the list compiled into
+// golang.org/x/net/publicsuffix.
+package publicsuffix
+
+import (
+	"net/http/cookiejar"
+	"sync/atomic"
+
+	"github.com/lynxsecurity/domain"
+	"golang.org/x/net/publicsuffix"
+)
+
+var current atomic.Pointer[domain.Domain]
+
+// Use sets the Domain the package level functions and List look names up in
+func Use(d *domain.Domain) {
+	current.Store(d)
+}
+
+// PublicSuffix returns the public suffix of the domain, see
+// domain.Domain.PublicSuffix
+func PublicSuffix(domain string) (publicSuffix string, icann bool) {
+	if d := current.Load(); d != nil {
+		return d.PublicSuffix(domain)
+	}
+	return publicsuffix.PublicSuffix(domain)
+}
+
+// EffectiveTLDPlusOne returns the public suffix of the domain plus one more
+// label, see domain.Domain.EffectiveTLDPlusOne
+func EffectiveTLDPlusOne(domain string) (string, error) {
+	if d := current.Load(); d != nil {
+		return d.EffectiveTLDPlusOne(domain)
+	}
+	return publicsuffix.EffectiveTLDPlusOne(domain)
+}
+
+// List implements cookiejar.PublicSuffixList with the Domain given to Use
+var List cookiejar.PublicSuffixList = list{}
+
+type list struct{}
+
+func (list) PublicSuffix(domain string) string {
+	ps, _ := PublicSuffix(domain)
+	return ps
+}
+
+func (list) String() string {
+	if d := current.Load(); d != nil {
+		return "github.com/lynxsecurity/domain " + d.ListVersion().Version
+	}
+	return publicsuffix.List.String()
+}
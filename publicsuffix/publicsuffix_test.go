@@ -0,0 +1,85 @@
+package publicsuffix
+
+import (
+	"os"
+	"testing"
+
+	"github.com/lynxsecurity/domain"
+	"github.com/lynxsecurity/domain/domaintest"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/publicsuffix"
+)
+
+func TestPublicSuffix(t *testing.T) {
+	Use(domaintest.New(t))
+	tests := []struct {
+		i     string
+		ps    string
+		icann bool
+		etld1 string
+		err   string
+	}{
+		{i: "www.example.co.uk", ps: "co.uk", icann: true, etld1: "example.co.uk"},
+		{i: "foo.github.io", ps: "github.io", icann: false, etld1: "foo.github.io"},
+		{i: "a.b.example.ck", ps: "example.ck", icann: true, etld1: "b.example.ck"},
+		{i: "www.ck", ps: "ck", icann: true, etld1: "www.ck"},
+		{i: "example.nonexist", ps: "nonexist", icann: false, etld1: "example.nonexist"},
+		{i: "co.uk", ps: "co.uk", icann: true, err: `publicsuffix: cannot derive eTLD+1 for domain "co.uk"`},
+		{i: "example.com.", ps: "", icann: false, err: `publicsuffix: empty label in domain "example.com."`},
+	}
+	for _, ts := range tests {
+		ps, icann := PublicSuffix(ts.i)
+		assert.Equal(t, ts.ps, ps, ts.i)
+		assert.Equal(t, ts.icann, icann, ts.i)
+		etld1, err := EffectiveTLDPlusOne(ts.i)
+		if ts.err != "" {
+			assert.EqualError(t, err, ts.err)
+			continue
+		}
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.etld1, etld1, ts.i)
+	}
+	assert.Equal(t, "co.uk", List.PublicSuffix("www.example.co.uk"))
+	assert.Equal(t, "github.com/lynxsecurity/domain domaintest", List.String())
+}
+
+// differentialHosts are names whose rules have been stable for years, so the
+// list compiled into x/net and the snapshot in testdata agree on them
+var differentialHosts = []string{
+	"com", "example.com", "www.example.com", "a.b.c.example.com",
+	"co.uk", "example.co.uk", "www.example.co.uk",
+	"github.io", "foo.github.io", "www.foo.github.io",
+	"blogspot.com", "foo.blogspot.com",
+	"kobe.jp", "c.kobe.jp", "b.c.kobe.jp", "city.kobe.jp", "www.city.kobe.jp",
+	"ck", "www.ck", "www.www.ck", "example.ck", "a.example.ck",
+	"nonexist", "example.nonexist", "www.example.nonexist",
+	"xn--p1ai", "example.xn--p1ai",
+	"example.com.", ".example.com", "example..com", "",
+}
+
+func TestDifferential(t *testing.T) {
+	defer current.Store(nil)
+	d, err := domain.NewFromFS(os.DirFS("../testdata"), "public_suffix_list.dat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, use := range []*domain.Domain{nil, d} {
+		current.Store(use)
+		for _, host := range differentialHosts {
+			wantPS, wantICANN := publicsuffix.PublicSuffix(host)
+			ps, icann := PublicSuffix(host)
+			assert.Equal(t, wantPS, ps, host)
+			assert.Equal(t, wantICANN, icann, host)
+			wantETLD1, wantErr := publicsuffix.EffectiveTLDPlusOne(host)
+			etld1, err := EffectiveTLDPlusOne(host)
+			assert.Equal(t, wantETLD1, etld1, host)
+			assert.Equal(t, wantErr, err, host)
+		}
+	}
+}
+
+func TestWithoutUse(t *testing.T) {
+	current.Store(nil)
+	assert.Equal(t, "co.uk", List.PublicSuffix("www.example.co.uk"))
+	assert.Equal(t, publicsuffix.List.String(), List.String())
+}
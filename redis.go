@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedisClient is the part of a Redis client used by RedisBackend, so the
+// module does not depend on a particular client library. An adapter for
+// go-redis or redigo is a few lines per method.
+type RedisClient interface {
+	// Get returns the value of key, or nil and no error if it is not set
+	Get(key string) ([]byte, error)
+	// Set sets key to value without expiry
+	Set(key string, value []byte) error
+	// SetNX sets key to value with the given expiry if it is not set yet and
+	// reports whether it did
+	SetNX(key string, value []byte, ttl time.Duration) (bool, error)
+	// Publish sends message on channel
+	Publish(channel, message string) error
+	// Subscribe delivers the messages of channel until cancel is called
+	Subscribe(channel string) (messages <-chan string, cancel func(), err error)
+}
+
+// RedisBackend is a CacheBackend that keeps the suffix list in one Redis key
+// shared by a fleet of workers. Workers call Sync to reload whenever the list
+// changes, and only the worker holding the short lived leader key downloads
+// the list from publicsuffix.org.
+type RedisBackend struct {
+	client RedisClient
+	key    string
+	id     string
+}
+
+// NewRedisBackend returns a backend storing the list under key, id names this
+// worker in the leader key and should be unique in the fleet
+func NewRedisBackend(client RedisClient, key, id string) *RedisBackend {
+	return &RedisBackend{client: client, key: key, id: id}
+}
+
+// redis key suffixes derived from the list key
+const (
+	redisLeaderSuffix  = ":leader"
+	redisChannelSuffix = ":updated"
+	redisStoredHeader  = "// STORED: "
+)
+
+// Load reads the list from Redis
+func (r *RedisBackend) Load() ([]byte, time.Time, error) {
+	list, err := r.client.Get(r.key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if list == nil {
+		return nil, time.Time{}, ErrCacheMiss
+	}
+	return list, redisStored(list), nil
+}
+
+// Store writes the list to Redis, prefixed with the time it was stored, and
+// tells the other workers to reload it
+func (r *RedisBackend) Store(list []byte) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s%s\n", redisStoredHeader, time.Now().UTC().Format(time.RFC3339))
+	buf.Write(list)
+	if err := r.client.Set(r.key, buf.Bytes()); err != nil {
+		return err
+	}
+	return r.client.Publish(r.key+redisChannelSuffix, r.id)
+}
+
+// redisStored reads the store time written by Store, zero if there is none
+func redisStored(list []byte) time.Time {
+	line, _ := bufio.NewReader(bytes.NewReader(list)).ReadString('\n')
+	if !strings.HasPrefix(line, redisStoredHeader) {
+		return time.Time{}
+	}
+	t, _ := time.Parse(time.RFC3339, strings.TrimSpace(line[len(redisStoredHeader):]))
+	return t
+}
+
+// Sync keeps d, which must use r through WithCacheBackend, in step with the
+// fleet: d reloads whenever another worker stores a new list, and every
+// interval the worker that takes the leader key refreshes the list. onError,
+// which may be nil, receives reload and refresh failures. Call the returned
+// function to stop.
+func (r *RedisBackend) Sync(d *Domain, interval time.Duration, onError func(error)) (stop func(), err error) {
+	if d.opts.backend != CacheBackend(r) {
+		return nil, fmt.Errorf("redis: domain does not use this backend")
+	}
+	messages, cancel, err := r.client.Subscribe(r.key + redisChannelSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %v", err)
+	}
+	report := func(err error) {
+		if err != nil && onError != nil {
+			onError(err)
+		}
+	}
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case id, ok := <-messages:
+				if !ok {
+					report(fmt.Errorf("redis: subscription closed"))
+					messages = nil
+					continue
+				}
+				// d already holds the list this worker stored
+				if id != r.id {
+					report(d.Reload())
+				}
+			case <-ticker.C:
+				leader, err := r.client.SetNX(r.key+redisLeaderSuffix, []byte(r.id), interval)
+				if err != nil {
+					report(fmt.Errorf("redis: %v", err))
+				} else if leader {
+					report(d.Refresh())
+				}
+			}
+		}
+	}()
+	return func() {
+		once.Do(func() {
+			close(done)
+			cancel()
+		})
+	}, nil
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedis is an in-memory RedisClient
+type fakeRedis struct {
+	mu   sync.Mutex
+	keys map[string][]byte
+	subs map[string][]chan string
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{keys: make(map[string][]byte), subs: make(map[string][]chan string)}
+}
+
+func (f *fakeRedis) Get(key string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.keys[key], nil
+}
+
+func (f *fakeRedis) Set(key string, value []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys[key] = value
+	return nil
+}
+
+func (f *fakeRedis) SetNX(key string, value []byte, ttl time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.keys[key]; ok {
+		return false, nil
+	}
+	f.keys[key] = value
+	return true, nil
+}
+
+func (f *fakeRedis) Publish(channel, message string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, c := range f.subs[channel] {
+		select {
+		case c <- message:
+		default:
+		}
+	}
+	return nil
+}
+
+func (f *fakeRedis) Subscribe(channel string) (<-chan string, func(), error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := make(chan string, 1)
+	f.subs[channel] = append(f.subs[channel], c)
+	return c, func() {}, nil
+}
+
+func TestRedisBackend(t *testing.T) {
+	list := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "// VERSION: 1\ncom\n")
+	}))
+	defer list.Close()
+	listURL := func(o *options) { o.listURL = list.URL }
+
+	redis := newFakeRedis()
+	leader := NewRedisBackend(redis, "psl", "worker-1")
+	follower := NewRedisBackend(redis, "psl", "worker-2")
+
+	d1, err := New("", WithCacheBackend(leader), listURL)
+	assert.NoError(t, err)
+	d2, err := New("", WithCacheBackend(follower), WithOffline())
+	assert.NoError(t, err)
+	assert.Equal(t, "1", d2.ListVersion().Version)
+	_, stored, err := follower.Load()
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), stored, time.Minute)
+
+	_, err = leader.Sync(d2, time.Hour, nil)
+	assert.Error(t, err)
+	stop, err := follower.Sync(d2, time.Hour, func(err error) { t.Error(err) })
+	assert.NoError(t, err)
+	defer stop()
+
+	// a store by any worker reloads the others
+	assert.NoError(t, leader.Store([]byte("// VERSION: 2\ncom\nnet\n")))
+	deadline := time.Now().Add(3 * time.Second)
+	for d2.ListVersion().Version != "2" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, "2", d2.ListVersion().Version)
+	_, err = d2.Parse("example.net")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", d1.ListVersion().Version)
+
+	// the storing worker loads its list once, not again for its own message
+	var reloads int32
+	d1.OnRulesChanged(func(UpdateReport) { atomic.AddInt32(&reloads, 1) })
+	stop1, err := leader.Sync(d1, time.Hour, func(err error) { t.Error(err) })
+	assert.NoError(t, err)
+	defer stop1()
+	assert.NoError(t, d1.Refresh())
+	assert.NoError(t, leader.Store([]byte("// VERSION: 3\ncom\nnet\norg\n")))
+	deadline = time.Now().Add(3 * time.Second)
+	for d2.ListVersion().Version != "3" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, "3", d2.ListVersion().Version)
+	assert.Equal(t, "1", d1.ListVersion().Version)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&reloads))
+}
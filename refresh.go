@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// UpdateReport describes one refresh of the suffix list made by
+// RefreshReport or StartAutoRefresh
+type UpdateReport struct {
+	// Time is when the refresh finished
+	Time time.Time
+	// Previous and Current are the list versions before and after the
+	// refresh, they are equal when the refresh failed
+	Previous, Current ListVersion
+	// Added and Removed are the rules that appeared in or disappeared from
+	// the list, in list order
+	Added, Removed []Rule
+	// Err is set when the list could not be downloaded or stored, the
+	// Domain then keeps using the rules it had
+	Err error
+}
+
+// Changed reports whether the refresh added or removed any rule
+func (r UpdateReport) Changed() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0
+}
+
+// Affecting returns the added and removed rules that cover host, the rules
+// whose change can move the registrable domain of host. A rule covers the
+// names it applies to: "co.uk" and "*.uk" cover "example.co.uk", and so does
+// the exception "!example.co.uk".
+func (r UpdateReport) Affecting(host string) []Rule {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	var rules []Rule
+	for _, list := range [][]Rule{r.Added, r.Removed} {
+		for _, rule := range list {
+			if host == rule.Suffix || strings.HasSuffix(host, "."+rule.Suffix) {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
+}
+
+// StartAutoRefresh downloads the suffix list every interval, swaps the new
+// rules in while lookups continue and reports the outcome of every attempt
+// to onUpdate, which may be nil. Call the returned function to stop.
+func (d *Domain) StartAutoRefresh(interval time.Duration, onUpdate func(UpdateReport)) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			report := d.RefreshReport()
+			if onUpdate != nil {
+				onUpdate(report)
+			}
+		}
+	}()
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// RefreshReport works like Refresh and reports the rules the new list added
+// and removed, the error of Refresh is in the Err field
+func (d *Domain) RefreshReport() UpdateReport {
+	before, previous := d.Rules(), d.ListVersion()
+	err := d.Refresh()
+	report := UpdateReport{Time: time.Now(), Previous: previous, Current: d.ListVersion(), Err: err}
+	if err == nil {
+		report.Added, report.Removed = diffRules(before, d.Rules())
+	}
+	return report
+}
+
+// diffRules returns the rules only found in b and the rules only found in a
+func diffRules(a, b []Rule) (added, removed []Rule) {
+	inA := make(map[string]bool, len(a))
+	for _, r := range a {
+		inA[r.String()] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, r := range b {
+		inB[r.String()] = true
+		if !inA[r.String()] {
+			added = append(added, r)
+		}
+	}
+	for _, r := range a {
+		if !inB[r.String()] {
+			removed = append(removed, r)
+		}
+	}
+	return added, removed
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartAutoRefresh(t *testing.T) {
+	list := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "// VERSION: 2\ncom\nnet\n")
+	}))
+	defer list.Close()
+
+	o := newOptions(nil)
+	o.listURL = list.URL
+	d := newDomain("", strings.NewReader("// VERSION: 1\ncom\norg\n"), &o)
+
+	reports := make(chan UpdateReport, 1)
+	stop := d.StartAutoRefresh(10*time.Millisecond, func(r UpdateReport) {
+		select {
+		case reports <- r:
+		default:
+		}
+	})
+	defer stop()
+
+	select {
+	case r := <-reports:
+		assert.NoError(t, r.Err)
+		assert.True(t, r.Changed())
+		assert.Equal(t, "1", r.Previous.Version)
+		assert.Equal(t, "2", r.Current.Version)
+		assert.Equal(t, []string{"net"}, ruleTexts(r.Added))
+		assert.Equal(t, []string{"org"}, ruleTexts(r.Removed))
+	case <-time.After(3 * time.Second):
+		t.Fatal("no update reported")
+	}
+	stop()
+	stop()
+
+	_, err := d.Parse("example.net")
+	assert.NoError(t, err)
+}
+
+func TestRefreshReport(t *testing.T) {
+	list := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "// VERSION: 2\n// ===BEGIN ICANN DOMAINS===\ncom\nuk\nco.uk\n*.ck\n// ===END ICANN DOMAINS===\n")
+	}))
+	defer list.Close()
+
+	o := newOptions(nil)
+	o.listURL = list.URL
+	list1 := "// VERSION: 1\n// ===BEGIN ICANN DOMAINS===\ncom\nuk\nco.uk\n// ===END ICANN DOMAINS===\n// ===BEGIN PRIVATE DOMAINS===\nblogspot.co.uk\n// ===END PRIVATE DOMAINS===\n"
+	d := newDomain("", strings.NewReader(list1), &o)
+
+	r := d.RefreshReport()
+	assert.NoError(t, r.Err)
+	assert.Equal(t, []Rule{{Suffix: "ck", Kind: RuleWildcard, Section: SectionICANN, Source: PublicSuffixSource}}, r.Added)
+	assert.Equal(t, []Rule{{Suffix: "blogspot.co.uk", Kind: RuleNormal, Section: SectionPrivate, Source: PublicSuffixSource}}, r.Removed)
+	assert.Equal(t, r.Removed, r.Affecting("Shop.Blogspot.co.uk"))
+	assert.Equal(t, r.Added, r.Affecting("www.example.ck"))
+	assert.Empty(t, r.Affecting("www.example.co.uk"))
+
+	d.opts.offline = true
+	r = d.RefreshReport()
+	assert.Error(t, r.Err)
+	assert.False(t, r.Changed())
+}
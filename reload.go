@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reload re-reads the cache file, backend or suffix provider, picking up a
+// list that another process replaced since the Domain was created
+func (d *Domain) Reload() error {
+	if d.opts.provider != nil {
+		rules, version, err := providerRules(&d.opts)
+		if err != nil {
+			return fmt.Errorf("reload: %v", err)
+		}
+		d.install(rules, version, nil)
+		return nil
+	}
+	if d.opts.backend != nil {
+		list, _, err := d.opts.backend.Load()
+		if err != nil {
+			return fmt.Errorf("reload: %v", err)
+		}
+		d.load(bytes.NewReader(list))
+		return nil
+	}
+	if d.Cache == "" {
+		return fmt.Errorf("reload: domain has no cache file")
+	}
+	info, _ := os.Stat(d.Cache)
+	list, err := readCache(d.Cache)
+	if err != nil {
+		return err
+	}
+	d.recordCacheFile(info)
+	d.load(bytes.NewReader(list))
+	return nil
+}
+
+// Refresh downloads a fresh copy of the suffix list, atomically replaces the
+// cache file with it and reloads the rules. A Domain using a SuffixProvider
+// loads the rules from the provider again instead.
+func (d *Domain) Refresh() error {
+	err := d.refresh()
+	d.countRefresh(err)
+	return err
+}
+
+// refresh implements Refresh
+func (d *Domain) refresh() error {
+	if d.opts.provider != nil {
+		return d.Reload()
+	}
+	if d.opts.offline {
+		return fmt.Errorf("refresh: offline mode is enabled")
+	}
+	var list bytes.Buffer
+	if err := downloadList(&list, &d.opts); err != nil {
+		return err
+	}
+	if d.opts.backend != nil {
+		if err := d.opts.backend.Store(list.Bytes()); err != nil {
+			return fmt.Errorf("refresh: %v", err)
+		}
+	} else if d.Cache != "" {
+		lock, err := lockCache(d.Cache, true)
+		if err != nil {
+			return err
+		}
+		err = writeCache(d.Cache, list.Bytes(), d.opts.compressCache)
+		info, _ := os.Stat(d.Cache)
+		lock.unlock()
+		if err != nil {
+			return err
+		}
+		d.recordCacheFile(info)
+	}
+	d.load(&list)
+	if d.opts.rootZone {
+		return d.refreshRootZone()
+	}
+	return nil
+}
+
+// Watch checks the cache file every interval and reloads the Domain when it
+// has been replaced or modified, onError (which may be nil) receives reload
+// failures. Call the returned function to stop watching.
+func (d *Domain) Watch(interval time.Duration, onError func(error)) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+	last, _ := os.Stat(d.Cache)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(d.Cache)
+			if err != nil || !cacheChanged(last, info) {
+				continue
+			}
+			last = info
+			if !d.cacheReplaced() {
+				continue
+			}
+			if err := d.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}()
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// autoReloadPollInterval is how often the cache is checked when the platform
+// has no file notifications
+const autoReloadPollInterval = time.Second
+
+// autoReload reloads d whenever its cache file changes, using file
+// notifications where available and polling otherwise
+func (d *Domain) autoReload(onError func(error)) (stop func()) {
+	stop, err := notifyCache(d.Cache, func() {
+		if !d.cacheReplaced() {
+			return
+		}
+		if err := d.Reload(); err != nil && onError != nil {
+			onError(err)
+		}
+	})
+	if err != nil {
+		return d.Watch(autoReloadPollInterval, onError)
+	}
+	return stop
+}
+
+// Close stops the automatic reloading started by WithAutoReload, it is safe
+// to call on any Domain and more than once
+func (d *Domain) Close() error {
+	d.closeOnce.Do(func() {
+		if d.stopReload != nil {
+			d.stopReload()
+		}
+	})
+	return nil
+}
+
+// recordCacheFile notes the cache file the rules are loaded from. Reloads
+// call it before installing the rules, so a watcher woken by the write in the
+// meantime does not load the same file twice.
+func (d *Domain) recordCacheFile(info os.FileInfo) {
+	d.mu.Lock()
+	d.publish(func(s *ruleSnapshot) { s.cacheFile = info })
+	d.mu.Unlock()
+}
+
+// cacheReplaced reports whether the cache file differs from the one the rules
+// were loaded from, false for the file Refresh just wrote and loaded itself
+func (d *Domain) cacheReplaced() bool {
+	info, err := os.Stat(d.Cache)
+	return err != nil || cacheChanged(d.snapshot().cacheFile, info)
+}
+
+// cacheChanged reports whether a cache file was replaced or written to
+func cacheChanged(before, after os.FileInfo) bool {
+	if before == nil {
+		return true
+	}
+	return !os.SameFile(before, after) ||
+		!before.ModTime().Equal(after.ModTime()) ||
+		before.Size() != after.Size()
+}
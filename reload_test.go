@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchReloadsReplacedCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cacheFile := filepath.Join(dir, "tld.cache")
+	assert.NoError(t, writeCache(cacheFile, []byte("com\n"), false))
+
+	d, err := New(cacheFile, WithOffline())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = d.Parse("example.net")
+	assert.Error(t, err)
+
+	stop := d.Watch(10*time.Millisecond, func(err error) { t.Error(err) })
+	defer stop()
+	assert.NoError(t, writeCache(cacheFile, []byte("// VERSION: 2\ncom\nnet\n"), false))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for d.ListVersion().Version != "2" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	r, err := d.Parse("example.net")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "", Name: "example", TLD: "net"}, r)
+}
+
+func TestReloadWithoutCache(t *testing.T) {
+	d := newDomain("", strings.NewReader("com\n"), &options{})
+	assert.Error(t, d.Reload())
+}
+
+func TestAutoReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cacheFile := filepath.Join(dir, "tld.cache")
+	assert.NoError(t, writeCache(cacheFile, []byte("com\n"), false))
+
+	d, err := New(cacheFile, WithOffline(), WithAutoReload(func(err error) { t.Error(err) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	assert.NoError(t, writeCache(cacheFile, []byte("// VERSION: 2\ncom\nnet\n"), false))
+
+	deadline := time.Now().Add(3 * time.Second)
+	for d.ListVersion().Version != "2" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, "2", d.ListVersion().Version)
+	assert.NoError(t, d.Close())
+	assert.NoError(t, d.Close())
+}
+
+func TestAutoReloadSkipsOwnWrites(t *testing.T) {
+	dir, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cacheFile := filepath.Join(dir, "tld.cache")
+	assert.NoError(t, writeCache(cacheFile, []byte("com\n"), false))
+
+	dl := &cannedDownloader{list: "// VERSION: 2\ncom\nnet\n"}
+	d, err := New(cacheFile, WithDownloader(dl), WithAutoReload(func(err error) { t.Error(err) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Close()
+	var reloads int32
+	d.OnRulesChanged(func(UpdateReport) { atomic.AddInt32(&reloads, 1) })
+
+	assert.NoError(t, d.Refresh())
+	assert.Equal(t, "2", d.ListVersion().Version)
+	// give the watcher time to see the rename of the new cache file
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&reloads))
+
+	// a write by another process is still picked up, once
+	assert.NoError(t, writeCache(cacheFile, []byte("// VERSION: 3\ncom\nnet\norg\n"), false))
+	deadline := time.Now().Add(3 * time.Second)
+	for d.ListVersion().Version != "3" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, "3", d.ListVersion().Version)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&reloads))
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"container/list"
+	"sync"
+)
+
+// WithResultCache memoizes the records of up to n successfully parsed
+// inputs, evicting the least recently used, for streams such as access logs
+// where the same hosts repeat. Inputs are keyed after ASCII case folding,
+// unless WithPreserveCase is set. Every Parse returns its own copy of the
+// record, calls with ParseOption values bypass the cache, and the cache is
+// emptied whenever the rules change. Warnings about the list itself, such as
+// WithStaleWarning's, are not cached but added on every hit. Stats reports
+// its hits and misses.
+func WithResultCache(n int) Option {
+	return func(o *options) {
+		o.resultCache = n
+	}
+}
+
+// resultCache is a bounded LRU cache of parsed records
+type resultCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // front is the most recently used
+	// gen counts purges, so a record parsed with rules that were replaced
+	// meanwhile is not cached
+	gen uint64
+}
+
+// cachedRecord is the value of a resultCache list element
+type cachedRecord struct {
+	key string
+	rec *Record
+}
+
+func newResultCache(size int) *resultCache {
+	return &resultCache{size: size, entries: make(map[string]*list.Element, size), order: list.New()}
+}
+
+// get returns a copy of the record cached for key, or else the generation
+// to pass to put
+func (c *resultCache) get(key string) (*Record, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, c.gen, false
+	}
+	c.order.MoveToFront(e)
+	return copyRecord(e.Value.(*cachedRecord).rec), c.gen, true
+}
+
+// put caches a copy of rec for key, evicting the least recently used entry
+// when the cache is full. It does nothing if the cache was purged since gen
+// was returned by get.
+func (c *resultCache) put(key string, rec *Record, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*cachedRecord).rec = copyRecord(rec)
+		c.order.MoveToFront(e)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedRecord).key)
+	}
+	c.entries[key] = c.order.PushFront(&cachedRecord{key: key, rec: copyRecord(rec)})
+}
+
+// purge empties the cache
+func (c *resultCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element, c.size)
+	c.order.Init()
+	c.gen++
+}
+
+// copyRecord returns a deep copy of rec including its warnings
+func copyRecord(rec *Record) *Record {
+	c := rec.clone()
+	if len(rec.Warnings) > 0 {
+		c.Warnings = append([]string(nil), rec.Warnings...)
+	}
+	return c
+}
+
+// resultKey is the key input is cached under
+func (d *Domain) resultKey(input string) string {
+	if d.opts.preserveCase {
+		return input
+	}
+	return foldASCII(input)
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultCache(t *testing.T) {
+	d, err := NewFromList(strings.NewReader("com\nuk\nco.uk\n"), WithResultCache(2), WithServiceLabels())
+	assert.NoError(t, err)
+
+	first, err := d.Parse("_dmarc.www.example.co.uk")
+	assert.NoError(t, err)
+	first.ServiceLabels[0] = "changed"
+	first.Name = "changed"
+	again, err := d.Parse("_DMARC.WWW.Example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "www", Name: "example", TLD: "co.uk", ServiceLabels: []string{"_dmarc"}}, again)
+	s := d.Stats()
+	assert.Equal(t, uint64(1), s.ResultHits)
+	assert.Equal(t, uint64(1), s.ResultMisses)
+	assert.Equal(t, uint64(2), s.Parses)
+
+	// failures and calls with parse options are not cached
+	_, err = d.Parse("bad")
+	assert.Error(t, err)
+	_, err = d.Parse("bad")
+	assert.Error(t, err)
+	_, err = d.Parse("www.example.co.uk", WithExtraSuffixes("example.co.uk"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), d.Stats().ResultMisses)
+	assert.Equal(t, 1, d.results.order.Len())
+
+	// the least recently used entry is evicted
+	d.Parse("a.com")
+	d.Parse("_dmarc.www.example.co.uk")
+	d.Parse("b.com")
+	assert.Len(t, d.results.entries, 2)
+	assert.Contains(t, d.results.entries, "_dmarc.www.example.co.uk")
+	assert.NotContains(t, d.results.entries, "a.com")
+
+	// new rules empty the cache
+	d.load(strings.NewReader("com\n"))
+	assert.Equal(t, 0, d.results.order.Len())
+	_, err = d.Parse("www.example.co.uk")
+	assert.Error(t, err)
+
+	plain, _ := NewFromList(strings.NewReader("com\n"))
+	assert.Nil(t, plain.results)
+	_, err = NewFromList(strings.NewReader("com\n"), WithResultCache(-1))
+	assert.Error(t, err)
+}
+
+func TestResultCachePurgedGeneration(t *testing.T) {
+	c := newResultCache(4)
+	_, gen, ok := c.get("example.com")
+	assert.False(t, ok)
+	c.purge()
+	c.put("example.com", &Record{Name: "example", TLD: "com"}, gen)
+	_, _, ok = c.get("example.com")
+	assert.False(t, ok)
+}
+
+func TestResultCacheStaleWarning(t *testing.T) {
+	d, err := NewFromList(strings.NewReader("com\n"), WithResultCache(4), WithStaleWarning(time.Hour), WithUnknownTLDFallback())
+	assert.NoError(t, err)
+	r, err := d.Parse("www.example.com")
+	assert.NoError(t, err)
+	assert.Empty(t, r.Warnings)
+	r, err = d.Parse("db.corp")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`top level domain "corp" is not in the suffix list`}, r.Warnings)
+
+	// the list crosses the stale threshold while the records stay cached
+	d.mu.Lock()
+	d.publish(func(s *ruleSnapshot) { s.listTime = time.Now().Add(-2 * time.Hour) })
+	d.mu.Unlock()
+	r, err = d.Parse("www.example.com")
+	assert.NoError(t, err)
+	if assert.Len(t, r.Warnings, 1) {
+		assert.Contains(t, r.Warnings[0], "older than 1h0m0s")
+	}
+	r, err = d.Parse("db.corp")
+	assert.NoError(t, err)
+	if assert.Len(t, r.Warnings, 2) {
+		assert.Equal(t, `top level domain "corp" is not in the suffix list`, r.Warnings[0])
+		assert.Contains(t, r.Warnings[1], "older than 1h0m0s")
+	}
+	assert.Equal(t, uint64(2), d.Stats().ResultHits)
+	assert.Equal(t, uint64(2), d.Stats().ResultMisses)
+
+	// and back once the list is refreshed, with the entries still cached
+	d.mu.Lock()
+	d.publish(func(s *ruleSnapshot) { s.listTime = time.Now() })
+	d.mu.Unlock()
+	r, _ = d.Parse("www.example.com")
+	assert.Empty(t, r.Warnings)
+}
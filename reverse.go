@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// reverse DNS zones of RFC 1035 and RFC 3596
+const (
+	reverseIPv4 = "in-addr.arpa"
+	reverseIPv6 = "ip6.arpa"
+)
+
+// WithReverseDNS makes Parse decode complete reverse DNS names such as
+// "4.3.2.1.in-addr.arpa" into Record.IP. Their address labels are kept
+// together as the Name, "4.3.2.1" with TLD "in-addr.arpa", instead of being
+// split into a subdomain and a one label name.
+func WithReverseDNS() Option {
+	return func(o *options) {
+		o.reverseDNS = true
+	}
+}
+
+// IsReverse reports whether the record is a decoded reverse DNS name
+func (r *Record) IsReverse() bool {
+	return r.IP != nil
+}
+
+// ReverseIP decodes a complete reverse DNS name, four decimal labels under
+// "in-addr.arpa" or 32 hex nibbles under "ip6.arpa", into the address it
+// points to
+func ReverseIP(name string) (net.IP, bool) {
+	labels := SplitLabels(strings.ToLower(name))
+	n, ok := reverseLabels(labels)
+	if !ok {
+		return nil, false
+	}
+	return reverseAddress(labels[:n])
+}
+
+// reverseLabels returns the number of address labels of a name under one of
+// the reverse zones
+func reverseLabels(labels []string) (int, bool) {
+	zone := strings.Join(labels[max(len(labels)-2, 0):], ".")
+	switch {
+	case zone == reverseIPv4 && len(labels) == 4+2:
+		return 4, true
+	case zone == reverseIPv6 && len(labels) == 32+2:
+		return 32, true
+	}
+	return 0, false
+}
+
+// reverseAddress decodes the reversed address labels of a reverse DNS name
+func reverseAddress(labels []string) (net.IP, bool) {
+	if len(labels) == 4 {
+		ip := make(net.IP, 4)
+		for i, label := range labels {
+			n, err := strconv.Atoi(label)
+			if err != nil || n < 0 || n > 255 || len(label) > 1 && label[0] == '0' {
+				return nil, false
+			}
+			ip[3-i] = byte(n)
+		}
+		return ip.To16(), true
+	}
+	ip := make(net.IP, 16)
+	for i, label := range labels {
+		n, err := strconv.ParseUint(label, 16, 8)
+		if err != nil || len(label) != 1 {
+			return nil, false
+		}
+		pos := 31 - i
+		ip[pos/2] |= byte(n) << (4 * uint(1-pos%2))
+	}
+	return ip, true
+}
+
+// parseReverse fills rec from a complete reverse DNS name, reporting false
+// for any other name
+func parseReverse(rec *Record, labels []string) bool {
+	n, ok := reverseLabels(labels)
+	if !ok {
+		return false
+	}
+	ip, ok := reverseAddress(labels[:n])
+	if !ok {
+		return false
+	}
+	rec.IP = ip
+	rec.Name = strings.Join(labels[:n], ".")
+	rec.TLD = strings.Join(labels[n:], ".")
+	return true
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverseDNS(t *testing.T) {
+	ex := newTestDomain(t, WithReverseDNS())
+	r, err := ex.Parse("4.3.2.1.in-addr.arpa")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Name: "4.3.2.1", TLD: "in-addr.arpa", IP: net.ParseIP("1.2.3.4")}, r)
+	assert.True(t, r.IsReverse())
+	assert.Equal(t, "4.3.2.1.in-addr.arpa", r.Hostname())
+
+	v6 := "b.a.9.8.7.6.5.0.4.0.0.0.3.0.0.0.2.0.0.0.1.0.0.0.0.0.0.0.1.2.3.4.IP6.ARPA"
+	r, err = ex.Parse(v6)
+	assert.NoError(t, err)
+	assert.Equal(t, net.ParseIP("4321:0:1:2:3:4:567:89ab"), r.IP)
+	assert.Equal(t, "ip6.arpa", r.TLD)
+
+	// partial and malformed names are parsed as ordinary names
+	r, err = ex.Parse("3.2.1.in-addr.arpa")
+	assert.NoError(t, err)
+	assert.False(t, r.IsReverse())
+	r, err = ex.Parse("4.3.2.256.in-addr.arpa")
+	assert.NoError(t, err)
+	assert.Nil(t, r.IP)
+
+	ip, ok := ReverseIP("10.0.0.127.in-addr.arpa.")
+	assert.True(t, ok)
+	assert.Equal(t, net.ParseIP("127.0.0.10"), ip)
+	_, ok = ReverseIP("www.example.com")
+	assert.False(t, ok)
+
+	plain := newTestDomain(t)
+	r, _ = plain.Parse("4.3.2.1.in-addr.arpa")
+	assert.Equal(t, &Record{Subdomain: "4.3.2", Name: "1", TLD: "in-addr.arpa"}, r)
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// DefaultRootZoneURL is where the list of TLDs delegated in the DNS root zone
+// is downloaded from by WithRootZone
+const DefaultRootZoneURL = "https://data.iana.org/TLD/tlds-alpha-by-domain.txt"
+
+// Errors returned by Parse, wrapped with the offending name, when WithRootZone
+// is set and no suffix rule matches. They tell a name under a TLD that does
+// not exist at all, an internal or made up name, apart from one under a real
+// TLD that the cached suffix list does not know yet.
+var (
+	ErrNotDelegated   = errors.New("top level domain is not delegated in the root zone")
+	ErrUnlistedSuffix = errors.New("top level domain is delegated in the root zone but missing from the suffix list")
+)
+
+// rootZoneFile is where the root zone list of a cache file is kept
+func rootZoneFile(cacheFile string) string {
+	return cacheFile + ".root"
+}
+
+// downloadRootZone fetches the root zone TLD list and writes it to w as is
+func downloadRootZone(w io.Writer, o *options) error {
+	err := downloadFrom(w, o, []Downloader{HTTPDownloader{URL: o.rootZoneURL, Client: o.httpClient()}}, func(w io.Writer, r io.Reader) error {
+		_, err := io.Copy(w, r)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Could not download root zone: %v", err)
+	}
+	return nil
+}
+
+// loadRootZone downloads or reads the root zone list and installs it in d
+func (d *Domain) loadRootZone(o *options) error {
+	var list []byte
+	if d.Cache == "" {
+		var buf bytes.Buffer
+		if err := downloadRootZone(&buf, o); err != nil {
+			return err
+		}
+		list = buf.Bytes()
+	} else {
+		file := rootZoneFile(d.Cache)
+		if err := ensureFile(file, o, downloadRootZone); err != nil {
+			return err
+		}
+		lock, err := lockCache(file, false)
+		if err != nil {
+			return err
+		}
+		list, err = ioutil.ReadFile(file)
+		lock.unlock()
+		if err != nil {
+			return fmt.Errorf("Could not open root zone file: %v", err)
+		}
+		if list, err = decompressCache(list); err != nil {
+			return err
+		}
+	}
+	root := readRootZone(bytes.NewReader(list))
+	d.mu.Lock()
+	d.publish(func(s *ruleSnapshot) { s.rootZone = root })
+	d.mu.Unlock()
+	return nil
+}
+
+// refreshRootZone downloads a fresh root zone list, replaces the cached copy
+// and installs it in d
+func (d *Domain) refreshRootZone() error {
+	var list bytes.Buffer
+	if err := downloadRootZone(&list, &d.opts); err != nil {
+		return err
+	}
+	if d.Cache != "" {
+		file := rootZoneFile(d.Cache)
+		lock, err := lockCache(file, true)
+		if err != nil {
+			return err
+		}
+		err = writeCache(file, list.Bytes(), d.opts.compressCache)
+		lock.unlock()
+		if err != nil {
+			return err
+		}
+	}
+	root := readRootZone(&list)
+	d.mu.Lock()
+	d.publish(func(s *ruleSnapshot) { s.rootZone = root })
+	d.mu.Unlock()
+	return nil
+}
+
+// readRootZone reads one TLD per line, skipping "#" comments
+func readRootZone(r io.Reader) map[string]struct{} {
+	root := make(map[string]struct{})
+	b := bufio.NewScanner(r)
+	for b.Scan() {
+		line := strings.TrimSpace(b.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			root[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return root
+}
+
+// Delegated reports whether the rightmost label of name is a TLD delegated in
+// the root zone. It is always false unless the Domain was created with
+// WithRootZone.
+func (d *Domain) Delegated(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	tld := name[strings.LastIndexByte(name, '.')+1:]
+	if ascii, err := toASCII(tld); err == nil {
+		tld = ascii
+	}
+	_, ok := d.snapshot().rootZone[tld]
+	return ok
+}
+
+// unknownTLDError is the error Parse returns when no rule matches domain
+func (d *Domain) unknownTLDError(domain string) error {
+	checked := d.snapshot().rootZone != nil
+	switch {
+	case !checked:
+		return newParseError(domain, ErrCodeUnknownTLD, lastLabelOffset(domain), "top level domain does not exist", nil)
+	case d.Delegated(domain):
+		return newParseError(domain, ErrCodeUnlistedSuffix, lastLabelOffset(domain), "", ErrUnlistedSuffix)
+	}
+	return newParseError(domain, ErrCodeNotDelegated, lastLabelOffset(domain), "", ErrNotDelegated)
+}
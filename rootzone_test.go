@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRootZone(t *testing.T) {
+	dir, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cacheFile := filepath.Join(dir, "tld.cache")
+	assert.NoError(t, writeCache(cacheFile, []byte("com\n"), false))
+
+	hits := 0
+	root := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		fmt.Fprint(w, "# Version 2026101500, Last Updated Thu Oct 15 07:07:01 2026 UTC\nCOM\nNET\nXN--P1AI\n")
+	}))
+	defer root.Close()
+	rootURL := func(o *options) { o.rootZoneURL = root.URL }
+
+	d, err := New(cacheFile, WithRootZone(), rootURL)
+	assert.NoError(t, err)
+	_, err = d.Parse("example.com")
+	assert.NoError(t, err)
+	_, err = d.Parse("example.net")
+	assert.True(t, errors.Is(err, ErrUnlistedSuffix), err)
+	_, err = d.Parse("printer.corp")
+	assert.True(t, errors.Is(err, ErrNotDelegated), err)
+	assert.True(t, d.Delegated("пример.рф"))
+	assert.False(t, d.Delegated("printer.corp"))
+
+	// the root zone is cached next to the cache file
+	_, err = New(cacheFile, WithRootZone(), rootURL)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, hits)
+
+	d, err = New(cacheFile)
+	assert.NoError(t, err)
+	_, err = d.Parse("example.net")
+	assert.EqualError(t, err, "parse \"example.net\": top level domain does not exist")
+	assert.False(t, d.Delegated("example.com"))
+}
@@ -0,0 +1,132 @@
+package domain
+
+import "strings"
+
+// RuleKind is the kind of a suffix list rule
+type RuleKind int
+
+const (
+	// RuleNormal is a plain rule such as "co.uk"
+	RuleNormal RuleKind = iota
+	// RuleWildcard is a rule such as "*.ck" that makes every child of its
+	// suffix a public suffix
+	RuleWildcard
+	// RuleException is a rule such as "!www.ck" that carves a name out of a
+	// wildcard rule
+	RuleException
+)
+
+// String returns the name of the rule kind
+func (k RuleKind) String() string {
+	switch k {
+	case RuleNormal:
+		return "normal"
+	case RuleWildcard:
+		return "wildcard"
+	case RuleException:
+		return "exception"
+	}
+	return "unknown"
+}
+
+// Section is the part of the public suffix list a rule comes from
+type Section int
+
+const (
+	// SectionUnknown is used for rules of extra sources and of caches
+	// written before sections were recorded
+	SectionUnknown Section = iota
+	// SectionICANN holds the suffixes delegated by ICANN and registries
+	SectionICANN
+	// SectionPrivate holds suffixes submitted by private organisations
+	// such as hosting providers
+	SectionPrivate
+)
+
+// String returns the name of the section
+func (s Section) String() string {
+	switch s {
+	case SectionICANN:
+		return "ICANN"
+	case SectionPrivate:
+		return "private"
+	}
+	return "unknown"
+}
+
+// section markers of the public suffix list, kept in the cache
+const (
+	beginICANN   = "===BEGIN ICANN DOMAINS==="
+	endICANN     = "===END ICANN DOMAINS==="
+	beginPrivate = "===BEGIN PRIVATE DOMAINS==="
+	endPrivate   = "===END PRIVATE DOMAINS==="
+)
+
+// Rule is one rule of the loaded suffix list
+type Rule struct {
+	// Suffix is the name the rule applies to without its "*." or "!"
+	// marker, "ck" for "*.ck" and "www.ck" for "!www.ck"
+	Suffix  string
+	Kind    RuleKind
+	Section Section
+	// Source is the name of the source that provided the rule
+	Source string
+}
+
+// String formats the rule the way it is written in the suffix list
+func (r Rule) String() string {
+	switch r.Kind {
+	case RuleWildcard:
+		return "*." + r.Suffix
+	case RuleException:
+		return "!" + r.Suffix
+	}
+	return r.Suffix
+}
+
+// parseRule splits a suffix list line into a Rule
+func parseRule(line string, section Section, source string) Rule {
+	r := Rule{Suffix: line, Section: section, Source: source}
+	switch {
+	case strings.HasPrefix(line, "!"):
+		r.Kind, r.Suffix = RuleException, line[1:]
+	case strings.HasPrefix(line, "*."):
+		r.Kind, r.Suffix = RuleWildcard, line[2:]
+	}
+	return r
+}
+
+// sectionMarker returns the section that starts after a comment line, and
+// whether the line is a section marker at all
+func sectionMarker(comment string) (Section, bool) {
+	switch strings.TrimSpace(strings.TrimPrefix(comment, "//")) {
+	case beginICANN:
+		return SectionICANN, true
+	case beginPrivate:
+		return SectionPrivate, true
+	case endICANN, endPrivate:
+		return SectionUnknown, true
+	}
+	return SectionUnknown, false
+}
+
+// Rules returns a copy of every rule of the loaded suffix list, including
+// those merged in from extra sources, in list order
+func (d *Domain) Rules() []Rule {
+	return append([]Rule(nil), d.snapshot().rules...)
+}
+
+// RuleCount returns the number of suffix rules loaded, without copying them
+// like Rules does
+func (d *Domain) RuleCount() int {
+	return len(d.snapshot().rules)
+}
+
+// ruleTexts returns the suffix list lines of rules
+func ruleTexts(rules []Rule) []string {
+	texts := make([]string, len(rules))
+	for i, r := range rules {
+		texts[i] = r.String()
+	}
+	return texts
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules(t *testing.T) {
+	raw := `// ===BEGIN ICANN DOMAINS===
+com
+*.ck
+!www.ck
+// ===END ICANN DOMAINS===
+// ===BEGIN PRIVATE DOMAINS===
+github.io
+// ===END PRIVATE DOMAINS===
+`
+	var cache bytes.Buffer
+	assert.NoError(t, writeList(&cache, strings.NewReader(raw), time.Now()))
+	d := newDomain("", &cache, &options{})
+	assert.Equal(t, []Rule{
+		{Suffix: "com", Kind: RuleNormal, Section: SectionICANN, Source: PublicSuffixSource},
+		{Suffix: "ck", Kind: RuleWildcard, Section: SectionICANN, Source: PublicSuffixSource},
+		{Suffix: "www.ck", Kind: RuleException, Section: SectionICANN, Source: PublicSuffixSource},
+		{Suffix: "github.io", Kind: RuleNormal, Section: SectionPrivate, Source: PublicSuffixSource},
+	}, d.Rules())
+	assert.Equal(t, []string{"com", "*.ck", "!www.ck", "github.io"}, ruleTexts(d.Rules()))
+	assert.Equal(t, 4, d.RuleCount())
+
+	o := newOptions([]Option{WithSource(Source{Name: "corp", Rules: []string{"corp.example"}})})
+	cache.Reset()
+	assert.NoError(t, writeList(&cache, strings.NewReader(raw), time.Now()))
+	d = newDomain("", &cache, &o)
+	assert.Contains(t, d.Rules(), Rule{Suffix: "corp.example", Kind: RuleNormal, Section: SectionUnknown, Source: "corp"})
+	assert.Contains(t, d.Rules(), Rule{Suffix: "github.io", Kind: RuleNormal, Section: SectionPrivate, Source: PublicSuffixSource})
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// refanger undoes the usual ways threat intel feeds defang indicators
+var refanger = strings.NewReplacer(
+	"[.]", ".", "(.)", ".", "{.}", ".", "\\.", ".",
+	"[dot]", ".", "(dot)", ".", "{dot}", ".",
+	"[DOT]", ".", "(DOT)", ".", "{DOT}", ".",
+	"[:]", ":", "[://]", "://", "[@]", "@",
+)
+
+// defanger makes hostnames and URLs safe to paste into reports
+var defanger = strings.NewReplacer(".", "[.]", "://", "[://]")
+
+// defangedSchemes maps defanged URL schemes to the real ones
+var defangedSchemes = map[string]string{
+	"hxxp":    "http",
+	"hxxps":   "https",
+	"fxp":     "ftp",
+	"hxxp[s]": "https",
+}
+
+// Refang reverses common defanging such as "example[.]com" or
+// "hxxp://evil[.]net", returning a string that can be parsed
+func Refang(s string) string {
+	s = refanger.Replace(strings.TrimSpace(s))
+	if i := strings.Index(s, "://"); i > 0 {
+		if scheme, ok := defangedSchemes[strings.ToLower(s[:i])]; ok {
+			s = scheme + s[i:]
+		}
+	}
+	return s
+}
+
+// Defang rewrites a hostname or URL so that it cannot be clicked or resolved
+// by accident, "http://evil.net" becomes "hxxp[://]evil[.]net"
+func Defang(s string) string {
+	if i := strings.Index(s, "://"); i > 0 {
+		switch strings.ToLower(s[:i]) {
+		case "http":
+			s = "hxxp" + s[i:]
+		case "https":
+			s = "hxxps" + s[i:]
+		case "ftp":
+			s = "fxp" + s[i:]
+		}
+	}
+	return defanger.Replace(s)
+}
+
+// Defang returns the defanged hostname of the record, without the "*."
+// label of a wildcard record, which is not part of any host
+func (r *Record) Defang() string {
+	return Defang(strings.TrimPrefix(r.host(), "*."))
+}
+
+// lenientHost refangs s and strips any URL scheme, user info, path, query or
+// fragment around the host, keeping a port if present
+func lenientHost(s string) string {
+	s = Refang(s)
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	}
+	if i := strings.IndexAny(s, "/?#"); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.LastIndexByte(s, '@'); i >= 0 {
+		s = s[i+1:]
+	}
+	return s
+}
+
+// invisible reports whether r is a control character or an invisible format
+// character, such as a zero width joiner, byte order mark, soft hyphen or
+// bidi control, that hostnames pasted from documents often carry
+func invisible(r rune) bool {
+	return unicode.IsControl(r) || unicode.Is(unicode.Cf, r)
+}
+
+// invisibleIndex returns the byte offset of the first invisible character
+// in s, or -1 if there is none
+func invisibleIndex(s string) int {
+	return strings.IndexFunc(s, invisible)
+}
+
+// joiner reports whether r is a zero width non-joiner or joiner, which
+// IDNA2008 allows in some labels (CONTEXTJ)
+func joiner(r rune) bool {
+	return r == '\u200c' || r == '\u200d'
+}
+
+// invisibleNonJoiner reports whether r is invisible but not a joiner
+func invisibleNonJoiner(r rune) bool {
+	return invisible(r) && !joiner(r)
+}
+
+// stripInvisible removes every invisible character from s
+func stripInvisible(s string) string {
+	return stripFunc(s, invisible)
+}
+
+// stripFunc removes every character of s for which f returns true
+func stripFunc(s string, f func(rune) bool) string {
+	return strings.Map(func(r rune) rune {
+		if f(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefang(t *testing.T) {
+	tests := []struct {
+		i, o string
+	}{
+		{i: "example[.]com", o: "example.com"},
+		{i: "hxxp://evil[.]net", o: "http://evil.net"},
+		{i: "HXXPS[://]www(.)evil{.}net/path", o: "https://www.evil.net/path"},
+		{i: " evil[dot]co[DOT]uk ", o: "evil.co.uk"},
+		{i: "example\\.com", o: "example.com"},
+		{i: "example.com", o: "example.com"},
+	}
+	for _, ts := range tests {
+		assert.Equal(t, ts.o, Refang(ts.i), ts.i)
+	}
+}
+
+func TestDefang(t *testing.T) {
+	assert.Equal(t, "hxxp[://]evil[.]net/a[.]html", Defang("http://evil.net/a.html"))
+	assert.Equal(t, "www[.]example[.]com", Defang("www.example.com"))
+	assert.Equal(t, "blog[.]google", (&Record{Name: "blog", TLD: "google"}).Defang())
+	assert.Equal(t, "api[.]example[.]com", (&Record{Subdomain: "api", Name: "example", TLD: "com", Wildcard: true}).Defang())
+	assert.Equal(t, "evil.net", Refang(Defang("evil.net")))
+}
+
+func TestLenientInput(t *testing.T) {
+	tests := []struct {
+		i string
+		o *Record
+	}{
+		{i: "example[.]com", o: &Record{Name: "example", TLD: "com"}},
+		{i: "hxxps://user@www.evil[.]co[.]uk:8443/login?x=1", o: &Record{Subdomain: "www", Name: "evil", TLD: "co.uk", Port: "8443"}},
+		{i: "http://example.com/", o: &Record{Name: "example", TLD: "com"}},
+	}
+	ex := newTestDomain(t, WithLenientInput())
+	strict := newTestDomain(t)
+	for _, ts := range tests {
+		r, err := ex.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+		_, err = strict.Parse(ts.i)
+		assert.Error(t, err, ts.i)
+	}
+}
+
+func TestInvisibleCharacters(t *testing.T) {
+	tests := []struct {
+		i      string
+		offset int
+		msg    string
+	}{
+		{i: "pay\u200dpal.com", offset: 3, msg: "parse \"pay\u200dpal.com\": domain name cannot contain invisible character U+200D"},
+		{i: "\ufeffpaypal.com", offset: 0, msg: "parse \"\ufeffpaypal.com\": domain name cannot contain invisible character U+FEFF"},
+		{i: "pay\u00adpal.com", offset: 3, msg: "parse \"pay\u00adpal.com\": domain name cannot contain invisible character U+00AD"},
+		{i: "paypal.com\u202e", offset: 10, msg: "parse \"paypal.com\u202e\": domain name cannot contain invisible character U+202E"},
+		{i: "paypal.com\r\n", offset: 10, msg: "parse \"paypal.com\r\n\": domain name cannot contain invisible character U+000D"},
+	}
+	ex := newTestDomain(t)
+	strict := newTestDomain(t, WithRejectInvisible())
+	for _, ts := range tests {
+		r, err := ex.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, &Record{Name: "paypal", TLD: "com"}, r, ts.i)
+
+		_, err = strict.Parse(ts.i)
+		if assert.IsType(t, &ParseError{}, err, ts.i) {
+			pe := err.(*ParseError)
+			assert.Equal(t, ErrCodeInvisibleCharacter, pe.Code)
+			assert.Equal(t, ts.offset, pe.Offset)
+			assert.Equal(t, ts.msg, pe.Error())
+		}
+	}
+}
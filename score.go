@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LabelFeatures are the features of one label that tell generated names
+// (DGA, tunnelling and exfiltration subdomains) from human chosen ones
+type LabelFeatures struct {
+	Label string
+	// Length is the length of the label in characters
+	Length int
+	// Entropy is the Shannon entropy of the label's characters in bits
+	Entropy float64
+	// DigitRatio is the share of characters that are digits
+	DigitRatio float64
+	// BigramLikelihood is the mean log10 probability of the label's letter
+	// pairs under a model of common English and web words, dictionary words
+	// score around -1.1 while random strings fall below -1.5
+	BigramLikelihood float64
+}
+
+// LabelScore holds the features of every label of a host left of its
+// public suffix, the name last
+type LabelScore struct {
+	Record *Record
+	Labels []LabelFeatures
+}
+
+// MaxEntropy returns the highest label entropy of the host
+func (s LabelScore) MaxEntropy() float64 {
+	max := 0.0
+	for _, l := range s.Labels {
+		max = math.Max(max, l.Entropy)
+	}
+	return max
+}
+
+// MinBigramLikelihood returns the lowest, most random looking, bigram
+// likelihood of the host's labels
+func (s LabelScore) MinBigramLikelihood() float64 {
+	min := 0.0
+	for _, l := range s.Labels {
+		min = math.Min(min, l.BigramLikelihood)
+	}
+	return min
+}
+
+// Score parses host and computes the features of its subdomain labels and
+// name, the public suffix is not scored
+func (d *Domain) Score(host string) (LabelScore, error) {
+	rec, err := d.Parse(host)
+	if err != nil {
+		return LabelScore{}, err
+	}
+	score := LabelScore{Record: rec}
+	labels := append([]string(nil), rec.ServiceLabels...)
+	if rec.Subdomain != "" {
+		labels = append(labels, strings.Split(rec.Subdomain, ".")...)
+	}
+	for _, label := range append(labels, rec.Name) {
+		score.Labels = append(score.Labels, ScoreLabel(label))
+	}
+	return score, nil
+}
+
+// ScoreLabel computes the features of a single label
+func ScoreLabel(label string) LabelFeatures {
+	label = strings.ToLower(label)
+	f := LabelFeatures{Label: label, Length: utf8.RuneCountInString(label)}
+	if f.Length == 0 {
+		return f
+	}
+	counts := make(map[rune]int)
+	digits := 0
+	for _, r := range label {
+		counts[r]++
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	n := float64(f.Length)
+	for _, c := range counts {
+		p := float64(c) / n
+		f.Entropy -= p * math.Log2(p)
+	}
+	f.DigitRatio = float64(digits) / n
+	f.BigramLikelihood = bigramLikelihood(label)
+	return f
+}
+
+// bigramAlphabet is "^" for a word boundary followed by the letters, any
+// other character counts as a boundary
+const bigramAlphabet = "^abcdefghijklmnopqrstuvwxyz"
+
+// bigramLog holds log10 P(b | a) for every pair of bigramAlphabet
+var bigramLog = trainBigrams(bigramCorpus)
+
+// trainBigrams builds an add-one smoothed bigram model from words
+func trainBigrams(corpus string) [27][27]float64 {
+	var counts [27][27]float64
+	for _, word := range strings.Fields(corpus) {
+		prev := 0
+		for _, r := range word {
+			cur := bigramIndex(r)
+			counts[prev][cur]++
+			prev = cur
+		}
+		counts[prev][0]++
+	}
+	var model [27][27]float64
+	for a := range counts {
+		total := 27.0
+		for _, c := range counts[a] {
+			total += c
+		}
+		for b, c := range counts[a] {
+			model[a][b] = math.Log10((c + 1) / total)
+		}
+	}
+	return model
+}
+
+// bigramIndex maps a character to its position in bigramAlphabet
+func bigramIndex(r rune) int {
+	if r >= 'a' && r <= 'z' {
+		return int(r-'a') + 1
+	}
+	return 0
+}
+
+// bigramLikelihood returns the mean log10 bigram probability of label
+func bigramLikelihood(label string) float64 {
+	sum, pairs := 0.0, 0
+	prev := 0
+	for _, r := range label {
+		cur := bigramIndex(r)
+		sum += bigramLog[prev][cur]
+		pairs++
+		prev = cur
+	}
+	sum += bigramLog[prev][0]
+	pairs++
+	return sum / float64(pairs)
+}
+
+// bigramCorpus is a list of common English and web words used to train
+// the bigram model
+const bigramCorpus = `
+the be to of and a in that have it for not on with he as you do at this but
+his by from they we say her she or an will my one all would there their what
+so up out if about who get which go me when make can like time no just him
+know take people into year your good some could them see other than then now
+look only come its over think also back after use two how our work first well
+way even new want because any these give day most us are is was were been has
+had did said each many more very through long where much should down own such
+find here thing between both life being under never same another while last
+might great old off come since against right still own place around however
+home small large next early young important few public private group problem
+fact hand high part world case week company system program question government
+number night point city play state family service school student country
+mail email login account secure update support help news shop store online
+cloud server host web site page app mobile data info media video music photo
+search blog forum wiki docs portal admin dashboard api cdn static assets images
+files download upload share connect network internet global local office team
+bank pay payment money card credit finance market trade business service center
+health care medical travel hotel book booking ticket event game games sport
+free best top world media digital smart tech technology software solution group
+north south east west central united national international first direct
+stream live chat social friend community member club market express mart
+green blue red black white gold silver star sun moon sky light fire water
+energy power auto car motor parts home house garden kitchen design studio art
+photo print press report review guide learn education academy university
+college school library museum gallery theatre travel tour tours trip flight
+insurance legal law lawyer consulting agency marketing media studio creative
+development developer staging production internal external partner partners
+customer customers client clients order orders cart checkout delivery shipping
+`
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreLabel(t *testing.T) {
+	f := ScoreLabel("aaaa")
+	assert.Equal(t, 4, f.Length)
+	assert.Equal(t, 0.0, f.Entropy)
+	assert.Equal(t, 0.0, f.DigitRatio)
+
+	f = ScoreLabel("ab12")
+	assert.InDelta(t, 2.0, f.Entropy, 1e-9)
+	assert.InDelta(t, 0.5, f.DigitRatio, 1e-9)
+
+	word := ScoreLabel("shopping")
+	random := ScoreLabel("xjq7zkvw2qf")
+	assert.True(t, word.BigramLikelihood > random.BigramLikelihood, "%v <= %v", word.BigramLikelihood, random.BigramLikelihood)
+	assert.True(t, random.Entropy > word.Entropy)
+}
+
+func TestDomainScore(t *testing.T) {
+	ex := newTestDomain(t)
+	s, err := ex.Score("a1b2c3d4e5f6.login.example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a1b2c3d4e5f6", "login", "example"}, []string{s.Labels[0].Label, s.Labels[1].Label, s.Labels[2].Label})
+	assert.InDelta(t, 0.5, s.Labels[0].DigitRatio, 1e-9)
+	assert.Equal(t, s.Labels[0].Entropy, s.MaxEntropy())
+	assert.Equal(t, s.Labels[0].BigramLikelihood, s.MinBigramLikelihood())
+
+	_, err = ex.Score("bad")
+	assert.Error(t, err)
+}
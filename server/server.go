@@ -0,0 +1,179 @@
+// Package server exposes a domain.Domain as a small JSON API, so programs
+// that are not written in Go can use the same public suffix logic.
+//
+// Every lookup endpoint takes the hostname in the "host" query parameter:
+//
+//	GET /parse?host=www.example.co.uk        the parsed record
+//	GET /registrable?host=www.example.co.uk  {"registrable": "example.co.uk"}
+//	GET /levels?host=a.b.example.com         every level of the host
+//	GET /healthz                             200 while the process runs
+//	GET /readyz                              200 while the suffix list is usable
+//
+// Failures are answered with a status code and {"error": "..."}: 400 for a
+// malformed host, 413 for a host over the input limits of the Domain and 422
+// for a well formed host that has no registrable domain.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lynxsecurity/domain"
+)
+
+// Server serves the JSON API for a Domain
+type Server struct {
+	d      *domain.Domain
+	mux    *http.ServeMux
+	maxAge time.Duration
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithMaxListAge makes /readyz fail once the loaded suffix list is older than
+// age, see domain.Domain.IsStale
+func WithMaxListAge(age time.Duration) Option {
+	return func(s *Server) {
+		s.maxAge = age
+	}
+}
+
+// Record is the JSON form of a parsed domain.Record
+type Record struct {
+	Host          string   `json:"host"`
+	Subdomain     string   `json:"subdomain"`
+	Name          string   `json:"name"`
+	TLD           string   `json:"tld"`
+	Registrable   string   `json:"registrable"`
+	Port          string   `json:"port,omitempty"`
+	ServiceLabels []string `json:"service_labels,omitempty"`
+	Warnings      []string `json:"warnings,omitempty"`
+}
+
+// New returns a Server answering lookups with d
+func New(d *domain.Domain, opts ...Option) *Server {
+	s := &Server{d: d, mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.mux.HandleFunc("/parse", s.lookup(s.parse))
+	s.mux.HandleFunc("/registrable", s.lookup(s.registrable))
+	s.mux.HandleFunc("/levels", s.lookup(s.levels))
+	s.mux.HandleFunc("/healthz", s.healthz)
+	s.mux.HandleFunc("/readyz", s.readyz)
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// lookup checks the method and host parameter shared by the lookup endpoints
+func (s *Server) lookup(fn func(host string) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		host := r.URL.Query().Get("host")
+		if host == "" {
+			writeError(w, http.StatusBadRequest, "missing host parameter")
+			return
+		}
+		v, err := fn(host)
+		if err != nil {
+			writeError(w, errorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, v)
+	}
+}
+
+// errorStatus maps the code of a parse error to the status answering it
+func errorStatus(err error) int {
+	var pe *domain.ParseError
+	if !errors.As(err, &pe) {
+		return http.StatusUnprocessableEntity
+	}
+	switch pe.Code {
+	case domain.ErrCodeInputTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case domain.ErrCodeInvalidCharacter, domain.ErrCodeNoDot, domain.ErrCodeEmptyLabel,
+		domain.ErrCodeIPAddress, domain.ErrCodeInvalidPort, domain.ErrCodeInvisibleCharacter,
+		domain.ErrCodeIDNA, domain.ErrCodeLabelTooLong, domain.ErrCodeNameTooLong:
+		return http.StatusBadRequest
+	}
+	return http.StatusUnprocessableEntity
+}
+
+func (s *Server) parse(host string) (interface{}, error) {
+	rec, err := s.d.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+	return Record{
+		Host:          rec.Hostname(),
+		Subdomain:     rec.Subdomain,
+		Name:          rec.Name,
+		TLD:           rec.TLD,
+		Registrable:   strings.ToLower(rec.Name + "." + rec.TLD),
+		Port:          rec.Port,
+		ServiceLabels: rec.ServiceLabels,
+		Warnings:      rec.Warnings,
+	}, nil
+}
+
+func (s *Server) registrable(host string) (interface{}, error) {
+	rec, err := s.d.Parse(host)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"registrable": strings.ToLower(rec.Name + "." + rec.TLD)}, nil
+}
+
+func (s *Server) levels(host string) (interface{}, error) {
+	levels, err := s.d.LevelsE(host)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]string{"levels": levels}, nil
+}
+
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// readyz reports ready while the Domain holds suffix rules, its last refresh
+// succeeded and, with WithMaxListAge, the list is not stale, along with the
+// version of the loaded list
+func (s *Server) readyz(w http.ResponseWriter, r *http.Request) {
+	if s.d.RuleCount() == 0 {
+		writeError(w, http.StatusServiceUnavailable, "no suffix rules loaded")
+		return
+	}
+	if err := s.d.Stats().LastRefreshErr; err != nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Sprintf("last refresh failed: %v", err))
+		return
+	}
+	if s.maxAge > 0 && s.d.IsStale(s.maxAge) {
+		writeError(w, http.StatusServiceUnavailable, fmt.Sprintf("suffix list is older than %s", s.maxAge))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready", "list": s.d.ListVersion().String()})
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
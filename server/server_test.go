@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/lynxsecurity/domain"
+	"github.com/lynxsecurity/domain/domaintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	s := New(domaintest.New(t))
+	get := func(path string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), path)
+		return w.Code, body
+	}
+
+	code, body := get("/parse?host=www.example.co.uk")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "www", body["subdomain"])
+	assert.Equal(t, "example", body["name"])
+	assert.Equal(t, "co.uk", body["tld"])
+	assert.Equal(t, "example.co.uk", body["registrable"])
+
+	code, body = get("/registrable?host=a.b.example.com")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "example.com", body["registrable"])
+
+	code, body = get("/levels?host=a.example.com")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{"a.example.com", "example.com"}, body["levels"])
+
+	code, body = get("/parse?host=example.nonexist")
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.NotEmpty(t, body["error"])
+	code, _ = get("/parse?host=co.uk")
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	code, _ = get("/parse?host=bad")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = get("/registrable?host=exa%20mple.com")
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = get("/levels?host=example.com:http")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = get("/parse")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = get("/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	code, body = get("/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", body["status"])
+}
+
+func TestServerInputLimits(t *testing.T) {
+	s := New(domaintest.New(t, domain.WithInputLimits(40, 4)))
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/parse?host=a.b.c.d.example.com", nil))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+type failingDownloader struct{}
+
+func (failingDownloader) Fetch(ctx context.Context) (io.ReadCloser, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestReadyz(t *testing.T) {
+	readyz := func(s *Server) (int, string) {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var body map[string]string
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body["error"]
+	}
+
+	// a failed refresh leaves the old list loaded but the server not ready
+	d := domaintest.New(t, domain.WithDownloader(failingDownloader{}))
+	code, _ := readyz(New(d))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Error(t, d.Refresh())
+	code, msg := readyz(New(d))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, msg, "last refresh failed")
+
+	old := fstest.MapFS{"list.dat": {Data: []byte(domaintest.List), ModTime: time.Now().Add(-72 * time.Hour)}}
+	d, err := domain.NewFromFS(old, "list.dat")
+	assert.NoError(t, err)
+	code, _ = readyz(New(d))
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = readyz(New(d, WithMaxListAge(96*time.Hour)))
+	assert.Equal(t, http.StatusOK, code)
+	code, msg = readyz(New(d, WithMaxListAge(48*time.Hour)))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "suffix list is older than 48h0m0s", msg)
+}
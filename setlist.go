@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// SetList atomically replaces the rules of a live Domain with rules written
+// as in the suffix list, "com", "*.ck" or "!www.ck". Sources added with
+// WithSource are merged in again. Concurrent rule lookups see either the old
+// or the new rules, never a mix.
+func (d *Domain) SetList(rules []string) error {
+	var list strings.Builder
+	for _, rule := range rules {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		if err := checkRule(rule); err != nil {
+			return fmt.Errorf("set list: %v", err)
+		}
+		list.WriteString(rule)
+		list.WriteByte('\n')
+	}
+	d.load(strings.NewReader(list.String()))
+	return nil
+}
+
+// ReplaceFrom atomically replaces the rules of a live Domain with a suffix
+// list in the publicsuffix.org format read from r, see SetList
+func (d *Domain) ReplaceFrom(r io.Reader) error {
+	var list bytes.Buffer
+	if err := writeList(&list, r, time.Now()); err != nil {
+		return fmt.Errorf("replace: %v", err)
+	}
+	d.load(&list)
+	return nil
+}
+
+// checkRule checks that rule is a suffix list rule with no empty labels
+func checkRule(rule string) error {
+	bare := strings.TrimPrefix(strings.TrimPrefix(rule, "!"), "*.")
+	if bare == "" || strings.Contains(bare, "..") || strings.HasPrefix(bare, ".") || strings.HasSuffix(bare, ".") ||
+		strings.ContainsAny(bare, " \t*!") {
+		return fmt.Errorf("invalid rule \"%s\"", rule)
+	}
+	return nil
+}
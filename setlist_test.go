@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetList(t *testing.T) {
+	d := newDomain("", strings.NewReader("com\n"), &options{})
+	_, err := d.Parse("example.net")
+	assert.Error(t, err)
+
+	assert.NoError(t, d.SetList([]string{"net", "*.ck", "!www.ck"}))
+	r, err := d.Parse("example.net")
+	assert.NoError(t, err)
+	assert.Equal(t, "net", r.TLD)
+	_, err = d.Parse("example.com")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"net", "*.ck", "!www.ck"}, ruleTexts(d.Rules()))
+
+	assert.Error(t, d.SetList([]string{"org", "a..b"}))
+	_, err = d.Parse("example.net")
+	assert.NoError(t, err)
+
+	assert.NoError(t, d.ReplaceFrom(strings.NewReader("// VERSION: 3\n\norg\n")))
+	assert.Equal(t, "3", d.ListVersion().Version)
+	_, err = d.Parse("example.org")
+	assert.NoError(t, err)
+}
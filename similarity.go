@@ -0,0 +1,129 @@
+package domain
+
+import "strings"
+
+// Score is how alike two records are, every field runs from 0 for nothing
+// in common to 1 for the same
+type Score struct {
+	// Total weighs Name at 0.6 and TLD and Subdomain at 0.2 each
+	Total float64
+	// Name is one minus the edit distance of the names, counted in
+	// characters with adjacent transpositions as one edit and divided by
+	// the longer name. Names that share a Skeleton but are spelled
+	// differently, "examp1e" and "example", score 0.95.
+	Name float64
+	// TLD is 1 for the same public suffix, 0.75 for suffixes under the same
+	// top level domain such as "co.uk" and "org.uk", 0.5 for top level
+	// domains of the same TLDCategory and 0 otherwise
+	TLD float64
+	// Subdomain is the share of subdomain tokens, the labels split at "-"
+	// and "_", that the other record has anywhere in its host, so
+	// "example.com.login.net" scores 1 against "example.com". It is 1 when
+	// neither record has a subdomain.
+	Subdomain float64
+}
+
+// Similarity scores how close a is to b, such as a suspicious host to one of
+// your own domains. Punycode labels are compared in their Unicode form.
+func Similarity(a, b *Record) Score {
+	s := Score{
+		Name:      nameSimilarity(unicodeName(strings.ToLower(a.Name)), unicodeName(strings.ToLower(b.Name))),
+		TLD:       tldSimilarity(unicodeName(strings.ToLower(a.TLD)), unicodeName(strings.ToLower(b.TLD))),
+		Subdomain: subdomainSimilarity(a, b),
+	}
+	s.Total = 0.6*s.Name + 0.2*s.TLD + 0.2*s.Subdomain
+	return s
+}
+
+// nameSimilarity scores the edit distance of two names
+func nameSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	if Skeleton(a) == Skeleton(b) {
+		return 0.95
+	}
+	ra, rb := []rune(a), []rune(b)
+	longer := max(len(ra), len(rb))
+	return 1 - float64(editDistance(ra, rb))/float64(longer)
+}
+
+// editDistance is the optimal string alignment distance of a and b: the
+// insertions, deletions, substitutions and transpositions of adjacent
+// characters needed to turn a into b
+func editDistance(a, b []rune) int {
+	// rows i-2, i-1 and i of the distance matrix
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+// tldSimilarity scores how interchangeable two public suffixes are
+func tldSimilarity(a, b string) float64 {
+	switch {
+	case a == b:
+		return 1
+	case a[strings.LastIndexByte(a, '.')+1:] == b[strings.LastIndexByte(b, '.')+1:]:
+		return 0.75
+	case CategorizeTLD(a) == CategorizeTLD(b):
+		return 0.5
+	}
+	return 0
+}
+
+// subdomainSimilarity scores the subdomain tokens of each record found
+// anywhere in the host of the other
+func subdomainSimilarity(a, b *Record) float64 {
+	subA, subB := hostTokens(a.Subdomain), hostTokens(b.Subdomain)
+	if len(subA) == 0 && len(subB) == 0 {
+		return 1
+	}
+	allA, allB := hostTokens(a.host()), hostTokens(b.host())
+	shared, total := 0, 0
+	for token := range subA {
+		total++
+		if allB[token] {
+			shared++
+		}
+	}
+	for token := range subB {
+		if subA[token] {
+			continue
+		}
+		total++
+		if allA[token] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(total)
+}
+
+// hostTokens splits a name into the set of its labels split at "-" and "_",
+// in Unicode form
+func hostTokens(name string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, token := range strings.FieldsFunc(unicodeName(strings.ToLower(name)), func(r rune) bool {
+		return r == '.' || r == '-' || r == '_'
+	}) {
+		tokens[token] = true
+	}
+	return tokens
+}
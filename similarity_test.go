@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimilarity(t *testing.T) {
+	ours := &Record{Name: "example", TLD: "com"}
+	tests := []struct {
+		other               *Record
+		name, tld, sub, all float64
+	}{
+		{&Record{Name: "example", TLD: "com"}, 1, 1, 1, 1},
+		{&Record{Subdomain: "www", Name: "Example", TLD: "com"}, 1, 1, 0, 0.8},
+		{&Record{Name: "examp1e", TLD: "com"}, 0.95, 1, 1, 0.97},
+		{&Record{Name: "exmaple", TLD: "net"}, 1 - 1.0/7, 0.5, 1, 0.6*(1-1.0/7) + 0.3},
+		{&Record{Name: "example", TLD: "co.uk"}, 1, 0, 1, 0.8},
+		{&Record{Subdomain: "example.com", Name: "login", TLD: "net"}, 1 - 7.0/7, 0.5, 1, 0.3},
+	}
+	for _, ts := range tests {
+		s := Similarity(ts.other, ours)
+		assert.InDelta(t, ts.name, s.Name, 1e-9, ts.other.String())
+		assert.InDelta(t, ts.tld, s.TLD, 1e-9, ts.other.String())
+		assert.InDelta(t, ts.sub, s.Subdomain, 1e-9, ts.other.String())
+		assert.InDelta(t, ts.all, s.Total, 1e-9, ts.other.String())
+		assert.Equal(t, s, Similarity(ours, ts.other), "similarity is symmetric")
+	}
+
+	s := Similarity(&Record{Name: "example", TLD: "org.uk"}, &Record{Name: "example", TLD: "co.uk"})
+	assert.Equal(t, 0.75, s.TLD)
+	s = Similarity(&Record{Subdomain: "login-secure", Name: "a", TLD: "com"}, &Record{Subdomain: "secure", Name: "b", TLD: "com"})
+	assert.InDelta(t, 0.5, s.Subdomain, 1e-9)
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		d    int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"paypal", "paypla", 1},
+		{"bücher", "bucher", 1},
+	}
+	for _, ts := range tests {
+		assert.Equal(t, ts.d, editDistance([]rune(ts.a), []rune(ts.b)), ts.a)
+	}
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// hostSortKey is what hosts are ordered by, see CompareHosts
+type hostSortKey struct {
+	ok          bool
+	registrable string
+	// reversed holds the subdomain labels from right to left
+	reversed []string
+	host     string
+}
+
+// sortKey parses host into its sort key
+func (d *Domain) sortKey(host string) hostSortKey {
+	rec, err := d.Parse(host)
+	if err != nil {
+		return hostSortKey{host: strings.ToLower(host)}
+	}
+	k := hostSortKey{ok: true, registrable: rec.Name + "." + rec.TLD, host: rec.Hostname()}
+	var sub []string
+	sub = append(sub, rec.ServiceLabels...)
+	if rec.Subdomain != "" {
+		sub = append(sub, strings.Split(rec.Subdomain, ".")...)
+	}
+	slices.Reverse(sub)
+	k.reversed = sub
+	return k
+}
+
+// compareSortKeys orders parsed hosts by registrable domain and then by
+// their subdomain labels read from right to left, hosts that did not parse
+// come last in lexical order
+func compareSortKeys(a, b hostSortKey) int {
+	switch {
+	case a.ok != b.ok:
+		if a.ok {
+			return -1
+		}
+		return 1
+	case !a.ok:
+		return strings.Compare(a.host, b.host)
+	}
+	if c := strings.Compare(a.registrable, b.registrable); c != 0 {
+		return c
+	}
+	if c := slices.Compare(a.reversed, b.reversed); c != 0 {
+		return c
+	}
+	return strings.Compare(a.host, b.host)
+}
+
+// CompareHosts orders two hosts by registrable domain first and then by
+// their subdomain labels from right to left, so a registrable domain comes
+// right before its subdomains: "example.com", "api.example.com",
+// "db.api.example.com", "www.example.com", then "example.net". Hosts that do
+// not parse sort after all others. It returns -1, 0 or +1 like
+// strings.Compare.
+func (d *Domain) CompareHosts(a, b string) int {
+	return compareSortKeys(d.sortKey(a), d.sortKey(b))
+}
+
+// SortHosts sorts hosts in place in the order of CompareHosts, parsing every
+// host once
+func (d *Domain) SortHosts(hosts []string) {
+	keys := make(map[string]hostSortKey, len(hosts))
+	for _, h := range hosts {
+		if _, ok := keys[h]; !ok {
+			keys[h] = d.sortKey(h)
+		}
+	}
+	slices.SortStableFunc(hosts, func(a, b string) int {
+		return compareSortKeys(keys[a], keys[b])
+	})
+}
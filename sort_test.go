@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortHosts(t *testing.T) {
+	ex := newTestDomain(t)
+	hosts := []string{
+		"www.example.net",
+		"not a host",
+		"db.api.example.com",
+		"www.example.com",
+		"Example.com",
+		"api.example.com",
+		"a.example.co.uk",
+		"example.net",
+		"b.api.example.com",
+	}
+	ex.SortHosts(hosts)
+	assert.Equal(t, []string{
+		"a.example.co.uk",
+		"Example.com",
+		"api.example.com",
+		"b.api.example.com",
+		"db.api.example.com",
+		"www.example.com",
+		"example.net",
+		"www.example.net",
+		"not a host",
+	}, hosts)
+
+	assert.Equal(t, -1, ex.CompareHosts("example.com", "api.example.com"))
+	assert.Equal(t, 1, ex.CompareHosts("api.example.com", "example.com"))
+	assert.Equal(t, 0, ex.CompareHosts("WWW.example.com", "www.example.com"))
+	assert.Equal(t, 1, ex.CompareHosts("bad..host", "zzz.example.org"))
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// SpecialUsePolicy decides how Parse treats names under a special-use domain
+// such as "onion" or "local", see WithSpecialUse
+type SpecialUsePolicy int
+
+const (
+	// SpecialUseClassify parses special-use names with the suffix list like
+	// any other name and sets Record.SpecialUse on those that parse
+	SpecialUseClassify SpecialUsePolicy = iota
+	// SpecialUseAccept parses special-use names with the special-use domain
+	// as their suffix, so "printer.local" has Name "printer" and TLD "local"
+	SpecialUseAccept
+	// SpecialUseReject fails to parse special-use names with ErrSpecialUse
+	SpecialUseReject
+)
+
+// ErrSpecialUse is returned, wrapped with the offending name, when a
+// special-use name is parsed under SpecialUseReject
+var ErrSpecialUse = errors.New("name is under a special-use domain")
+
+// specialUseDomains are the special-use domain names of the IANA registry
+// that hostnames show up under: RFC 6761 ("localhost", "test", "example",
+// "invalid"), RFC 6762 ("local"), RFC 7686 ("onion"), RFC 8375 ("home.arpa")
+// and RFC 9476 ("alt"), plus "internal", reserved by ICANN for private use
+var specialUseDomains = map[string]bool{
+	"localhost": true, "test": true, "example": true, "invalid": true,
+	"local": true, "onion": true, "home.arpa": true, "alt": true,
+	"internal": true,
+}
+
+// WithSpecialUse sets how Parse treats names under special-use domains, the
+// default is SpecialUseClassify
+func WithSpecialUse(policy SpecialUsePolicy) Option {
+	return func(o *options) {
+		o.specialUse = policy
+	}
+}
+
+// specialUseSuffix returns the number of trailing labels that form a
+// special-use domain and that domain, or 0 and "" if there is none
+func specialUseSuffix(labels []string) (int, string) {
+	for n := len(labels); n > 0; n-- {
+		if name := strings.Join(labels[len(labels)-n:], "."); specialUseDomains[name] {
+			return n, name
+		}
+	}
+	return 0, ""
+}
+
+// specialUseStart applies the special-use policy of d to labels. It returns
+// the special-use domain, the start of the suffix when the policy overrides
+// the suffix list, and an error when the policy rejects the name.
+func (d *Domain) specialUseStart(domain string, labels []string) (special string, start int, override bool, err error) {
+	n, special := specialUseSuffix(labels)
+	if n == 0 {
+		return "", 0, false, nil
+	}
+	switch d.opts.specialUse {
+	case SpecialUseReject:
+		e := newParseError(domain, ErrCodeSpecialUse, len(strings.TrimSuffix(domain, "."))-len(special), "", ErrSpecialUse)
+		e.Label = special
+		return special, 0, false, e
+	case SpecialUseAccept:
+		return special, len(labels) - n, true, nil
+	}
+	return special, 0, false, nil
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecialUse(t *testing.T) {
+	classify := newTestDomain(t)
+	r, err := classify.Parse("duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion")
+	assert.NoError(t, err)
+	assert.Equal(t, "onion", r.SpecialUse)
+	_, err = classify.Parse("printer.local")
+	assert.Error(t, err)
+	r, _ = classify.Parse("www.example.com")
+	assert.Equal(t, "", r.SpecialUse)
+
+	accept := newTestDomain(t, WithSpecialUse(SpecialUseAccept))
+	tests := []struct {
+		i string
+		o *Record
+	}{
+		{i: "printer.local", o: &Record{Name: "printer", TLD: "local", SpecialUse: "local"}},
+		{i: "nas.router.home.arpa", o: &Record{Subdomain: "nas", Name: "router", TLD: "home.arpa", SpecialUse: "home.arpa"}},
+		{i: "api.localhost", o: &Record{Name: "api", TLD: "localhost", SpecialUse: "localhost"}},
+		{i: "www.site.test", o: &Record{Subdomain: "www", Name: "site", TLD: "test", SpecialUse: "test"}},
+	}
+	for _, ts := range tests {
+		r, err := accept.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.o, r, ts.i)
+	}
+	_, err = accept.Parse("home.arpa")
+	assert.Error(t, err)
+
+	reject := newTestDomain(t, WithSpecialUse(SpecialUseReject))
+	_, err = reject.Parse("duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion")
+	assert.True(t, errors.Is(err, ErrSpecialUse), err)
+	_, err = reject.Parse("www.example.com")
+	assert.NoError(t, err)
+}
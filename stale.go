@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// CacheAge returns how old the loaded suffix list is, measured from the
+// download time recorded in the cache or, for caches written before that was
+// recorded, from the modification time of the cache file
+func (d *Domain) CacheAge() (time.Duration, error) {
+	s := d.snapshot()
+	if s.listTimeErr != nil {
+		return 0, s.listTimeErr
+	}
+	return time.Since(s.listTime), nil
+}
+
+// listTime returns the time CacheAge measures from for a list of version.
+// install calls it once per list, so Parse never touches the cache file.
+func (d *Domain) listTime(version ListVersion) (time.Time, error) {
+	if !version.Downloaded.IsZero() {
+		return version.Downloaded, nil
+	}
+	if d.Cache == "" {
+		return time.Time{}, fmt.Errorf("cache age: download time of the suffix list is unknown")
+	}
+	info, err := os.Stat(d.Cache)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cache age: %v", err)
+	}
+	return info.ModTime(), nil
+}
+
+// IsStale reports whether the loaded suffix list is older than max, a list
+// whose age cannot be determined is considered stale
+func (d *Domain) IsStale(max time.Duration) bool {
+	age, err := d.CacheAge()
+	return err != nil || age > max
+}
+
+// addWarnings adds the non-fatal warnings that apply to every parsed record
+func (d *Domain) addWarnings(rec *Record) {
+	if w := d.staleWarning(); w != "" {
+		rec.Warnings = append(rec.Warnings, w)
+	}
+}
+
+// staleWarning returns the warning WithStaleWarning adds to parsed records,
+// or "" when the list is fresh enough
+func (d *Domain) staleWarning() string {
+	if d.opts.staleAfter <= 0 {
+		return ""
+	}
+	age, err := d.CacheAge()
+	switch {
+	case err != nil:
+		return "suffix list age is unknown"
+	case age > d.opts.staleAfter:
+		return fmt.Sprintf("suffix list is %s old, older than %s", age.Truncate(time.Second), d.opts.staleAfter)
+	}
+	return ""
+}
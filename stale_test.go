@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheAge(t *testing.T) {
+	var cache bytes.Buffer
+	assert.NoError(t, writeList(&cache, strings.NewReader("com\n"), time.Now().Add(-48*time.Hour)))
+	d := newDomain("", &cache, &options{staleAfter: 24 * time.Hour})
+	age, err := d.CacheAge()
+	assert.NoError(t, err)
+	assert.InDelta(t, float64(48*time.Hour), float64(age), float64(time.Minute))
+	assert.True(t, d.IsStale(24*time.Hour))
+	assert.False(t, d.IsStale(72*time.Hour))
+
+	r, err := d.Parse("example.com")
+	assert.NoError(t, err)
+	if assert.Len(t, r.Warnings, 1) {
+		assert.Contains(t, r.Warnings[0], "suffix list is 48h0m0s old")
+	}
+
+	// legacy caches fall back to the modification time of the file
+	dir, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cacheFile := filepath.Join(dir, "tld.cache")
+	assert.NoError(t, writeCache(cacheFile, []byte("com\n"), false))
+	d, err = New(cacheFile, WithOffline())
+	assert.NoError(t, err)
+	assert.False(t, d.IsStale(time.Hour))
+	r, _ = d.Parse("example.com")
+	assert.Nil(t, r.Warnings)
+
+	d = newDomain("", strings.NewReader("com\n"), &options{})
+	_, err = d.CacheAge()
+	assert.Error(t, err)
+	assert.True(t, d.IsStale(time.Hour))
+}
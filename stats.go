@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// Stats are counters of the work a Domain has done since it was created
+type Stats struct {
+	// Parses counts calls to Parse, including the ones made by Levels and
+	// the other helpers built on it
+	Parses uint64
+	// Failures counts failed parses by the code of their ParseError
+	Failures map[ParseErrorCode]uint64
+	// SuffixHits and SuffixMisses count the names that a suffix rule did
+	// and did not match
+	SuffixHits, SuffixMisses uint64
+	// ResultHits and ResultMisses count the parses answered and not
+	// answered from the cache of WithResultCache
+	ResultHits, ResultMisses uint64
+	// Refreshes and RefreshFailures count downloads of a new list
+	Refreshes, RefreshFailures uint64
+	// LastRefresh is when the last refresh finished and LastRefreshErr its
+	// error, nil if it succeeded
+	LastRefresh    time.Time
+	LastRefreshErr error
+}
+
+// FailureCount returns the total of Failures
+func (s Stats) FailureCount() uint64 {
+	var n uint64
+	for _, c := range s.Failures {
+		n += c
+	}
+	return n
+}
+
+// domainStats holds the live counters behind Stats
+type domainStats struct {
+	parses                     atomic.Uint64
+	failures                   [numErrCodes]atomic.Uint64
+	suffixHits, suffixMisses   atomic.Uint64
+	resultHits, resultMisses   atomic.Uint64
+	refreshes, refreshFailures atomic.Uint64
+
+	// guarded by Domain.mu
+	lastRefresh    time.Time
+	lastRefreshErr error
+}
+
+// Stats returns a snapshot of the counters of d
+func (d *Domain) Stats() Stats {
+	s := Stats{
+		Parses:          d.stats.parses.Load(),
+		Failures:        make(map[ParseErrorCode]uint64),
+		SuffixHits:      d.stats.suffixHits.Load(),
+		SuffixMisses:    d.stats.suffixMisses.Load(),
+		ResultHits:      d.stats.resultHits.Load(),
+		ResultMisses:    d.stats.resultMisses.Load(),
+		Refreshes:       d.stats.refreshes.Load(),
+		RefreshFailures: d.stats.refreshFailures.Load(),
+	}
+	for code := range d.stats.failures {
+		if n := d.stats.failures[code].Load(); n > 0 {
+			s.Failures[ParseErrorCode(code)] = n
+		}
+	}
+	d.mu.Lock()
+	s.LastRefresh, s.LastRefreshErr = d.stats.lastRefresh, d.stats.lastRefreshErr
+	d.mu.Unlock()
+	return s
+}
+
+// countParse records the outcome of one parse
+func (d *Domain) countParse(err error) {
+	d.stats.parses.Add(1)
+	if err == nil {
+		return
+	}
+	var code ParseErrorCode
+	var pe *ParseError
+	if errors.As(err, &pe) && int(pe.Code) < len(d.stats.failures) {
+		code = pe.Code
+	}
+	d.stats.failures[code].Add(1)
+}
+
+// countRefresh records the outcome of one refresh
+func (d *Domain) countRefresh(err error) {
+	d.stats.refreshes.Add(1)
+	if err != nil {
+		d.stats.refreshFailures.Add(1)
+	}
+	d.mu.Lock()
+	d.stats.lastRefresh, d.stats.lastRefreshErr = time.Now(), err
+	d.mu.Unlock()
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	d := newDomain("", strings.NewReader("com\n"), &options{})
+	d.Parse("www.example.com")
+	d.Parse("example.com")
+	d.Parse("example.nonexist")
+	d.Parse("a..com")
+	d.Parse("bad")
+	assert.Error(t, d.Refresh())
+
+	s := d.Stats()
+	assert.Equal(t, uint64(5), s.Parses)
+	assert.Equal(t, map[ParseErrorCode]uint64{ErrCodeUnknownTLD: 1, ErrCodeEmptyLabel: 1, ErrCodeNoDot: 1}, s.Failures)
+	assert.Equal(t, uint64(3), s.FailureCount())
+	assert.Equal(t, uint64(2), s.SuffixHits)
+	assert.Equal(t, uint64(1), s.SuffixMisses)
+	assert.Equal(t, uint64(1), s.Refreshes)
+	assert.Equal(t, uint64(1), s.RefreshFailures)
+	assert.False(t, s.LastRefresh.IsZero())
+	assert.Error(t, s.LastRefreshErr)
+}
+
+func TestStatsNewerCodes(t *testing.T) {
+	d, err := NewFromList(strings.NewReader("com\n"), WithRejectInvisible(), WithInputLimits(40, 4), WithIDNAProfile(IDNARegistration))
+	assert.NoError(t, err)
+	d.Parse("exa\u200bmple.com")
+	d.Parse(strings.Repeat("a", 50) + ".com")
+	d.Parse("a.b.c.d.example.com")
+	d.Parse("\u0301a.com")
+	d.countParse(newInputError("example.com", ErrCodeLabelTooLong, "label too long"))
+	d.countParse(newInputError("example.com", ErrCodeNameTooLong, "name too long"))
+
+	assert.Equal(t, map[ParseErrorCode]uint64{
+		ErrCodeInvisibleCharacter: 1,
+		ErrCodeInputTooLarge:      2,
+		ErrCodeIDNA:               1,
+		ErrCodeLabelTooLong:       1,
+		ErrCodeNameTooLong:        1,
+	}, d.Stats().Failures)
+
+	// every code has a name and a counter
+	for code := ErrCodeInvalidCharacter; code < numErrCodes; code++ {
+		assert.NotEqual(t, "unknown", code.String(), int(code))
+	}
+	assert.Equal(t, int(numErrCodes), len(d.stats.failures))
+}
@@ -0,0 +1,79 @@
+package domain
+
+import "sort"
+
+// ruleStore is the lookup structure holding the suffix rules of a
+// ruleSnapshot. A store is filled before it is published and only read after.
+type ruleStore interface {
+	exists(rule string) bool
+	len() int
+}
+
+// mapRules stores rules as map keys, the fastest store for lookups
+type mapRules map[string]struct{}
+
+func (m mapRules) exists(rule string) bool {
+	_, ok := m[rule]
+	return ok
+}
+
+func (m mapRules) add(rule string) {
+	m[rule] = struct{}{}
+}
+
+func (m mapRules) len() int {
+	return len(m)
+}
+
+// compactRules stores sorted rules back to back in a single byte slice.
+// ends[i] is the offset one past the end of rule i, so rule i spans
+// data[ends[i-1]:ends[i]].
+type compactRules struct {
+	data []byte
+	ends []uint32
+}
+
+// newCompactRules packs rules, dropping duplicates
+func newCompactRules(rules []string) *compactRules {
+	sorted := append([]string(nil), rules...)
+	sort.Strings(sorted)
+	size := 0
+	for _, rule := range sorted {
+		size += len(rule)
+	}
+	c := &compactRules{data: make([]byte, 0, size), ends: make([]uint32, 0, len(sorted))}
+	for i, rule := range sorted {
+		if i > 0 && rule == sorted[i-1] {
+			continue
+		}
+		c.data = append(c.data, rule...)
+		c.ends = append(c.ends, uint32(len(c.data)))
+	}
+	return c
+}
+
+// at returns the bytes of rule i, the comparisons below convert them to
+// strings without allocating
+func (c *compactRules) at(i int) []byte {
+	start := uint32(0)
+	if i > 0 {
+		start = c.ends[i-1]
+	}
+	return c.data[start:c.ends[i]]
+}
+
+// search returns the index of the first rule >= rule
+func (c *compactRules) search(rule string) int {
+	return sort.Search(len(c.ends), func(i int) bool {
+		return string(c.at(i)) >= rule
+	})
+}
+
+func (c *compactRules) exists(rule string) bool {
+	i := c.search(rule)
+	return i < len(c.ends) && string(c.at(i)) == rule
+}
+
+func (c *compactRules) len() int {
+	return len(c.ends)
+}
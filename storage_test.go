@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompactRules(t *testing.T) {
+	c := newCompactRules([]string{"com", "co.uk", "*.ck", "com", "!www.ck"})
+	assert.Equal(t, 4, c.len())
+	for _, rule := range []string{"com", "co.uk", "*.ck", "!www.ck"} {
+		assert.True(t, c.exists(rule), rule)
+	}
+	for _, rule := range []string{"", "uk", "co", "comm", "www.ck"} {
+		assert.False(t, c.exists(rule), rule)
+	}
+
+}
+
+func TestCompactStorageOption(t *testing.T) {
+	ex := newTestDomain(t, WithCompactStorage())
+	r, err := ex.Parse("www.super.long.subdomain.hacking.us.com")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "www.super.long.subdomain", Name: "hacking", TLD: "us.com"}, r)
+}
+
+func TestSnapshotSwap(t *testing.T) {
+	for _, compact := range []bool{false, true} {
+		var opts []Option
+		if compact {
+			opts = append(opts, WithCompactStorage())
+		}
+		d := newTestDomain(t, opts...)
+		before := d.snapshot()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				d.load(strings.NewReader("com\n// ===BEGIN PRIVATE DOMAINS===\nus.com\n"))
+			}
+		}()
+		for i := 0; i < 1000; i++ {
+			r, err := d.Parse("www.example.us.com")
+			if assert.NoError(t, err) {
+				assert.Equal(t, "us.com", r.TLD)
+			}
+			r, err = d.Parse("www.example.us.com", WithoutPrivate())
+			if assert.NoError(t, err) {
+				assert.Equal(t, "com", r.TLD)
+			}
+		}
+		<-done
+		assert.True(t, before.store.exists("net"), "published snapshots are never modified")
+		assert.False(t, d.snapshot().store.exists("net"))
+		assert.Equal(t, 2, d.RuleCount())
+	}
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// TakeoverFingerprint describes a hosting service whose CNAME targets can be
+// claimed by anyone once the original account releases them
+type TakeoverFingerprint struct {
+	Service string
+	// Suffixes are the names CNAME targets of the service end in
+	Suffixes []string
+}
+
+// TakeoverFingerprints is the bundled fingerprint set CheckTakeover matches
+// CNAME chains against
+var TakeoverFingerprints = []TakeoverFingerprint{
+	{Service: "Amazon S3", Suffixes: []string{"s3.amazonaws.com", "s3-website.amazonaws.com"}},
+	{Service: "GitHub Pages", Suffixes: []string{"github.io"}},
+	{Service: "Microsoft Azure", Suffixes: []string{"azurewebsites.net", "cloudapp.net", "cloudapp.azure.com", "trafficmanager.net", "blob.core.windows.net", "azureedge.net"}},
+	{Service: "Heroku", Suffixes: []string{"herokuapp.com", "herokudns.com", "herokussl.com"}},
+}
+
+// Resolver is the part of *net.Resolver CheckTakeover uses
+type Resolver interface {
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// TakeoverFinding is a level of a host whose CNAME chain points at a
+// takeover-prone service
+type TakeoverFinding struct {
+	// Host is the level the chain starts at
+	Host string
+	// Chain lists the CNAME targets in resolution order
+	Chain []string
+	// Service is the matched TakeoverFingerprint
+	Service string
+	// Dangling is set when the last target does not resolve, the strongest
+	// sign that the resource was released and can be claimed
+	Dangling bool
+}
+
+// maxCNAMEChain bounds the CNAME chains CheckTakeover follows
+const maxCNAMEChain = 10
+
+// CheckTakeover resolves the CNAME chain of every level of host, as returned
+// by LevelsE, and reports the levels whose chain reaches a service of
+// TakeoverFingerprints. A finding is a candidate for a manual check, not
+// proof: only Dangling ones are likely to be claimable. A nil resolver uses
+// net.DefaultResolver.
+func (d *Domain) CheckTakeover(ctx context.Context, host string, resolver Resolver) ([]TakeoverFinding, error) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	levels, err := d.LevelsE(host)
+	if err != nil {
+		return nil, err
+	}
+	var findings []TakeoverFinding
+	for _, level := range levels {
+		chain, err := cnameChain(ctx, resolver, level)
+		if err != nil {
+			return findings, err
+		}
+		service, ok := takeoverService(chain)
+		if !ok {
+			continue
+		}
+		f := TakeoverFinding{Host: level, Chain: chain, Service: service}
+		if _, err := resolver.LookupHost(ctx, chain[len(chain)-1]); err != nil {
+			if !notFound(err) {
+				return findings, err
+			}
+			f.Dangling = true
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+// cnameChain follows the CNAME records of host, a name that does not exist
+// has an empty chain
+func cnameChain(ctx context.Context, resolver Resolver, host string) ([]string, error) {
+	var chain []string
+	for len(chain) < maxCNAMEChain {
+		target, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			if notFound(err) {
+				break
+			}
+			return nil, err
+		}
+		target = strings.ToLower(strings.TrimSuffix(target, "."))
+		if target == "" || target == host {
+			break
+		}
+		chain = append(chain, target)
+		host = target
+	}
+	return chain, nil
+}
+
+// takeoverService returns the service of the first target of chain that
+// matches a fingerprint
+func takeoverService(chain []string) (string, bool) {
+	for _, target := range chain {
+		for _, fp := range TakeoverFingerprints {
+			for _, suffix := range fp.Suffixes {
+				if target == suffix || strings.HasSuffix(target, "."+suffix) {
+					return fp.Service, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// notFound reports whether err is a DNS lookup of a name that does not exist
+func notFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
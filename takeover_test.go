@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResolver struct {
+	cnames map[string]string
+	hosts  map[string][]string
+}
+
+func (f fakeResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	if target, ok := f.cnames[host]; ok {
+		return target + ".", nil
+	}
+	if _, ok := f.hosts[host]; ok {
+		return host + ".", nil
+	}
+	return "", &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (f fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := f.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestCheckTakeover(t *testing.T) {
+	ex := newTestDomain(t)
+	r := fakeResolver{
+		cnames: map[string]string{
+			"docs.shop.example.com": "shop-docs.github.io",
+			"shop.example.com":      "edge.example.net",
+			"edge.example.net":      "shop.herokuapp.com",
+		},
+		hosts: map[string][]string{
+			"shop.herokuapp.com": {"192.0.2.1"},
+			"example.com":        {"192.0.2.2"},
+		},
+	}
+	findings, err := ex.CheckTakeover(context.Background(), "docs.shop.example.com", r)
+	assert.NoError(t, err)
+	assert.Equal(t, []TakeoverFinding{
+		{Host: "docs.shop.example.com", Chain: []string{"shop-docs.github.io"}, Service: "GitHub Pages", Dangling: true},
+		{Host: "shop.example.com", Chain: []string{"edge.example.net", "shop.herokuapp.com"}, Service: "Heroku"},
+	}, findings)
+
+	findings, err = ex.CheckTakeover(context.Background(), "www.example.com", r)
+	assert.NoError(t, err)
+	assert.Empty(t, findings)
+
+	_, err = ex.CheckTakeover(context.Background(), "naan.example", r)
+	assert.Error(t, err)
+}
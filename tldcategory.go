@@ -0,0 +1,122 @@
+package domain
+
+import "strings"
+
+// TLDCategory is the IANA root zone database type of a top level domain
+type TLDCategory int
+
+const (
+	// TLDUnknown is used for an empty or invalid TLD
+	TLDUnknown TLDCategory = iota
+	// TLDGeneric is an open generic TLD, such as "com" or any new gTLD
+	TLDGeneric
+	// TLDCountryCode is a two letter or IDN country code TLD, such as "uk"
+	// or "рф"
+	TLDCountryCode
+	// TLDSponsored is a TLD run for a specific community, such as "edu"
+	TLDSponsored
+	// TLDGenericRestricted is a generic TLD with eligibility rules, such as
+	// "biz"
+	TLDGenericRestricted
+	// TLDInfrastructure is "arpa"
+	TLDInfrastructure
+)
+
+// String returns the IANA name of the category
+func (c TLDCategory) String() string {
+	switch c {
+	case TLDGeneric:
+		return "generic"
+	case TLDCountryCode:
+		return "country-code"
+	case TLDSponsored:
+		return "sponsored"
+	case TLDGenericRestricted:
+		return "generic-restricted"
+	case TLDInfrastructure:
+		return "infrastructure"
+	}
+	return "unknown"
+}
+
+// TLDCategory classifies the top label of the record's public suffix, so
+// "co.uk" is a country code TLD and "github.io" a generic one
+func (r *Record) TLDCategory() TLDCategory {
+	return CategorizeTLD(r.TLD)
+}
+
+// CategorizeTLD classifies the rightmost label of a suffix or hostname using
+// IANA's root zone database. Labels that are not listed as country code,
+// sponsored, restricted or infrastructure TLDs are reported as generic.
+func CategorizeTLD(name string) TLDCategory {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	tld := name[strings.LastIndexByte(name, '.')+1:]
+	if ascii, err := toASCII(tld); err == nil {
+		tld = ascii
+	}
+	switch {
+	case tld == "":
+		return TLDUnknown
+	case tld == "arpa":
+		return TLDInfrastructure
+	case sponsoredTLDs[tld]:
+		return TLDSponsored
+	case restrictedTLDs[tld]:
+		return TLDGenericRestricted
+	case len(tld) == 2 && isLetters(tld), idnCountryCodeTLDs[tld]:
+		return TLDCountryCode
+	}
+	return TLDGeneric
+}
+
+// isLetters checks that s only holds ASCII letters
+func isLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// sponsoredTLDs and restrictedTLDs are taken from
+// https://www.iana.org/domains/root/db
+var (
+	sponsoredTLDs = map[string]bool{
+		"aero": true, "asia": true, "cat": true, "coop": true, "edu": true,
+		"gov": true, "int": true, "jobs": true, "mil": true, "museum": true,
+		"post": true, "tel": true, "travel": true, "xxx": true,
+	}
+	restrictedTLDs = map[string]bool{"biz": true, "name": true, "pro": true}
+)
+
+// idnCountryCodeTLDs holds the internationalized country code TLDs in
+// punycode form
+var idnCountryCodeTLDs = map[string]bool{
+	"xn--2scrj9c": true, "xn--3e0b707e": true, "xn--3hcrj9c": true,
+	"xn--45br5cyl": true, "xn--45brj9c": true, "xn--4dbrk0ce": true,
+	"xn--54b7fta0cc": true, "xn--80ao21a": true, "xn--90a3ac": true,
+	"xn--90ae": true, "xn--90ais": true, "xn--clchc0ea0b2g2a9gcd": true,
+	"xn--d1alf": true, "xn--e1a4c": true, "xn--fiqs8s": true,
+	"xn--fiqz9s": true, "xn--fpcrj9c3d": true, "xn--fzc2c9e2c": true,
+	"xn--gecrj9c": true, "xn--h2breg3eve": true, "xn--h2brj9c": true,
+	"xn--h2brj9c8c": true, "xn--j1amh": true, "xn--j6w193g": true,
+	"xn--kprw13d": true, "xn--kpry57d": true, "xn--l1acc": true,
+	"xn--lgbbat1ad8j": true, "xn--mgb2ddes": true, "xn--mgb9awbf": true,
+	"xn--mgba3a4f16a": true, "xn--mgba3a4fra": true,
+	"xn--mgbaam7a8h": true, "xn--mgbah1a3hjkrd": true,
+	"xn--mgbai9a5eva00b": true, "xn--mgbai9azgqp6j": true,
+	"xn--mgbayh7gpa": true, "xn--mgbbh1a": true, "xn--mgbbh1a71e": true,
+	"xn--mgbc0a9azcg": true, "xn--mgbcpq6gpa1a": true,
+	"xn--mgberp4a5d4a87g": true, "xn--mgberp4a5d4ar": true,
+	"xn--mgbgu82a": true, "xn--mgbpl2fh": true,
+	"xn--mgbqly7c0a67fbc": true, "xn--mgbqly7cvafr": true,
+	"xn--mgbtf8fl": true, "xn--mgbtx2b": true, "xn--mgbx4cd0ab": true,
+	"xn--mix082f": true, "xn--mix891f": true, "xn--nnx388a": true,
+	"xn--node": true, "xn--o3cw4h": true, "xn--ogbpf8fl": true,
+	"xn--p1ai": true, "xn--pgbs0dh": true, "xn--q7ce6a": true,
+	"xn--qxa6a": true, "xn--qxam": true, "xn--rvc1e0am3e": true,
+	"xn--s9brj9c": true, "xn--wgbh1c": true, "xn--wgbl6a": true,
+	"xn--xkc2al3hye2a": true, "xn--xkc2dl3a5ee0h": true,
+	"xn--y9a3aq": true, "xn--yfro4i67o": true, "xn--ygbi2ammx": true,
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLDCategory(t *testing.T) {
+	tests := []struct {
+		i string
+		o TLDCategory
+	}{
+		{i: "www.example.com", o: TLDGeneric},
+		{i: "example.co.uk", o: TLDCountryCode},
+		{i: "example.xn--p1ai", o: TLDCountryCode},
+		{i: "пример.рф", o: TLDCountryCode},
+		{i: "mit.edu", o: TLDSponsored},
+		{i: "example.biz", o: TLDGenericRestricted},
+		{i: "1.in-addr.arpa", o: TLDInfrastructure},
+		{i: "user.github.io", o: TLDCountryCode},
+		{i: "blog.google", o: TLDGeneric},
+	}
+	ex := newTestDomain(t)
+	for _, ts := range tests {
+		r, err := ex.Parse(ts.i)
+		assert.NoError(t, err, ts.i)
+		assert.Equal(t, ts.o, r.TLDCategory(), ts.i)
+	}
+	assert.Equal(t, TLDUnknown, CategorizeTLD(""))
+	assert.Equal(t, "country-code", CategorizeTLD("uk").String())
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// TLDExtractResult is a split of a host with the fields and edge case
+// behaviour of Python's tldextract, so pipelines mixing both produce the
+// same output for the same input
+type TLDExtractResult struct {
+	Subdomain string `json:"subdomain"`
+	Domain    string `json:"domain"`
+	Suffix    string `json:"suffix"`
+	IsPrivate bool   `json:"is_private"`
+}
+
+// RegisteredDomain returns the domain and suffix joined by a dot, or an empty
+// string if either of them is empty
+func (r TLDExtractResult) RegisteredDomain() string {
+	if r.Suffix == "" || r.Domain == "" {
+		return ""
+	}
+	return r.Domain + "." + r.Suffix
+}
+
+// FQDN returns the non-empty parts joined by dots, or an empty string if
+// there is no suffix, or no domain under a suffix that is not private
+func (r TLDExtractResult) FQDN() string {
+	if r.Suffix == "" || (r.Domain == "" && !r.IsPrivate) {
+		return ""
+	}
+	var parts []string
+	for _, p := range []string{r.Subdomain, r.Domain, r.Suffix} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// tldextractScheme matches the scheme tldextract strips from its input, in
+// either case as schemes are case-insensitive
+var tldextractScheme = regexp.MustCompile(`^([a-zA-Z0-9+\-.]+:)?//`)
+
+// TLDExtract splits a host or URL the way tldextract.extract does. Unlike
+// Parse it never fails: a scheme, user info, port, path, query and fragment
+// are dropped, case is preserved, a name without a known suffix gets an empty
+// Suffix and its last label as Domain, a name that is only a suffix gets an
+// empty Domain, and IPv4 and bracketed IPv6 addresses are returned whole as
+// Domain. Rules of the private section are ignored unless includePrivate is
+// set, as with tldextract's include_psl_private_domains.
+func (d *Domain) TLDExtract(input string, includePrivate bool) TLDExtractResult {
+	netloc := tldextractScheme.ReplaceAllString(input, "")
+	netloc = cut(netloc, "/")
+	netloc = cut(netloc, "?")
+	netloc = cut(netloc, "#")
+	if i := strings.LastIndex(netloc, "@"); i >= 0 {
+		netloc = netloc[i+1:]
+	}
+	if strings.HasPrefix(netloc, "[") {
+		if end := strings.Index(netloc, "]"); end > 0 {
+			if ip := net.ParseIP(netloc[1:end]); ip != nil && ip.To4() == nil {
+				return TLDExtractResult{Domain: netloc[1:end]}
+			}
+		}
+	}
+	netloc = normalizeDots(netloc)
+	host := strings.TrimRight(strings.TrimSpace(cut(netloc, ":")), ".")
+
+	labels := strings.Split(host, ".")
+	po := &parseOptions{withoutPrivate: !includePrivate}
+	start, rule, found := d.matchRule(strings.Split(strings.ToLower(host), "."), po)
+	if !found {
+		start = len(labels)
+	}
+	if start == len(labels) && len(labels) == 4 && net.ParseIP(host).To4() != nil {
+		return TLDExtractResult{Domain: host}
+	}
+	var r TLDExtractResult
+	if found {
+		r.Suffix = strings.Join(labels[start:], ".")
+		r.IsPrivate = d.isPrivate(rule)
+	}
+	if start >= 2 {
+		r.Subdomain = strings.Join(labels[:start-1], ".")
+	}
+	if start > 0 {
+		r.Domain = labels[start-1]
+	}
+	return r
+}
+
+// cut returns s up to the first sep, or all of s if it has none
+func cut(s, sep string) string {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithSubdomain returns a copy of the record with its subdomain, service
+// labels and wildcard marker included, replaced by sub, which may hold
+// several labels ("api" or "v2.api"). The labels must pass the checks of
+// Parse and the resulting name the DNS length limits.
+func (r *Record) WithSubdomain(sub string) (*Record, error) {
+	sub = strings.ToLower(strings.Trim(sub, "."))
+	if sub == "" {
+		return r.WithoutSubdomain(), nil
+	}
+	if err := checkPart(sub); err != nil {
+		return nil, fmt.Errorf("with subdomain: %v", err)
+	}
+	c := r.clone()
+	c.Subdomain, c.ServiceLabels, c.Wildcard = sub, nil, false
+	if err := checkLengths(c.host()); err != nil {
+		return nil, fmt.Errorf("with subdomain: %v", err)
+	}
+	return c, nil
+}
+
+// WithoutSubdomain returns a copy of the record reduced to its registrable
+// domain, without subdomain, service labels or wildcard marker
+func (r *Record) WithoutSubdomain() *Record {
+	c := r.clone()
+	c.Subdomain, c.ServiceLabels, c.Wildcard = "", nil, false
+	return c
+}
+
+// WithTLD returns a copy of the record under the public suffix tld, such as
+// "net" or "co.uk". Only the syntax of tld is checked, use Domain.Build to
+// also check that it is a public suffix of the list. Provider and SpecialUse
+// are cleared as they depend on the suffix.
+func (r *Record) WithTLD(tld string) (*Record, error) {
+	tld = strings.ToLower(strings.Trim(tld, "."))
+	if tld == "" {
+		return nil, fmt.Errorf("with tld: missing top level domain")
+	}
+	if err := checkPart(tld); err != nil {
+		return nil, fmt.Errorf("with tld: %v", err)
+	}
+	c := r.clone()
+	c.TLD, c.Provider, c.SpecialUse = tld, "", ""
+	if err := checkLengths(c.host()); err != nil {
+		return nil, fmt.Errorf("with tld: %v", err)
+	}
+	return c, nil
+}
+
+// clone returns a deep copy of the record without its warnings, which
+// describe the parse the record came from
+func (r *Record) clone() *Record {
+	c := *r
+	c.ServiceLabels = append([]string(nil), r.ServiceLabels...)
+	if len(c.ServiceLabels) == 0 {
+		c.ServiceLabels = nil
+	}
+	c.IP = append([]byte(nil), r.IP...)
+	if len(c.IP) == 0 {
+		c.IP = nil
+	}
+	c.Warnings = nil
+	return &c
+}
+
+// checkPart checks the labels of part of a name the way Parse does
+func checkPart(part string) error {
+	for _, label := range strings.Split(part, ".") {
+		if label == "" {
+			return fmt.Errorf("\"%s\" contains an empty label", part)
+		}
+	}
+	for _, char := range badChars {
+		if strings.ContainsRune(part, char) {
+			return fmt.Errorf("\"%s\" cannot contain \"%c\"", part, char)
+		}
+	}
+	if i := invisibleIndex(part); i >= 0 {
+		return fmt.Errorf("\"%s\" contains an invisible character", part)
+	}
+	return nil
+}
+
+// Parent returns a copy of the record without its leftmost label, the
+// wildcard marker, a service label or a subdomain label in that order. It
+// returns false for a registrable domain, which has no parent record.
+func (r *Record) Parent() (*Record, bool) {
+	c := r.clone()
+	switch {
+	case c.Wildcard:
+		c.Wildcard = false
+	case len(c.ServiceLabels) > 0:
+		c.ServiceLabels = c.ServiceLabels[1:]
+		if len(c.ServiceLabels) == 0 {
+			c.ServiceLabels = nil
+		}
+	case c.Subdomain != "":
+		_, c.Subdomain, _ = strings.Cut(c.Subdomain, ".")
+	default:
+		return nil, false
+	}
+	return c, true
+}
+
+// Child returns a copy of the record with label added on the left, "*"
+// sets the wildcard marker. An underscore label is added to the service
+// labels of a record that has some, any other label turns them back into
+// subdomain labels. The label must pass the checks of Parse and the
+// resulting name the DNS length limits.
+func (r *Record) Child(label string) (*Record, error) {
+	label = strings.ToLower(label)
+	if r.Wildcard {
+		return nil, fmt.Errorf("child: \"%s\" is a wildcard", r)
+	}
+	c := r.clone()
+	switch {
+	case label == "*":
+		c.Wildcard = true
+		return c, nil
+	case label == "":
+		return nil, fmt.Errorf("child: missing label")
+	case strings.Contains(label, "."):
+		return nil, fmt.Errorf("child: \"%s\" is more than one label", label)
+	}
+	if err := checkPart(label); err != nil {
+		return nil, fmt.Errorf("child: %v", err)
+	}
+	switch {
+	case len(c.ServiceLabels) > 0 && strings.HasPrefix(label, "_"):
+		c.ServiceLabels = append([]string{label}, c.ServiceLabels...)
+	case len(c.ServiceLabels) > 0:
+		sub := append([]string{label}, c.ServiceLabels...)
+		if c.Subdomain != "" {
+			sub = append(sub, c.Subdomain)
+		}
+		c.Subdomain, c.ServiceLabels = strings.Join(sub, "."), nil
+	case c.Subdomain != "":
+		c.Subdomain = label + "." + c.Subdomain
+	default:
+		c.Subdomain = label
+	}
+	if err := checkLengths(c.host()); err != nil {
+		return nil, fmt.Errorf("child: %v", err)
+	}
+	return c, nil
+}
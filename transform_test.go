@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordTransformations(t *testing.T) {
+	r := &Record{Subdomain: "www", Name: "example", TLD: "co.uk", Port: "8443", ServiceLabels: []string{"_dmarc"}, Warnings: []string{"stale"}}
+
+	api, err := r.WithSubdomain(".V2.API.")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "v2.api", Name: "example", TLD: "co.uk", Port: "8443"}, api)
+	assert.Equal(t, "www", r.Subdomain)
+	assert.Equal(t, []string{"_dmarc"}, r.ServiceLabels)
+
+	assert.Equal(t, &Record{Name: "example", TLD: "co.uk", Port: "8443"}, r.WithoutSubdomain())
+	same, err := r.WithSubdomain("")
+	assert.NoError(t, err)
+	assert.Equal(t, r.WithoutSubdomain(), same)
+
+	moved, err := (&Record{Subdomain: "www", Name: "example", TLD: "com", Provider: "x"}).WithTLD("NET")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "www", Name: "example", TLD: "net"}, moved)
+
+	_, err = r.WithSubdomain("a..b")
+	assert.EqualError(t, err, `with subdomain: "a..b" contains an empty label`)
+	_, err = r.WithSubdomain("my api")
+	assert.EqualError(t, err, `with subdomain: "my api" cannot contain " "`)
+	_, err = r.WithSubdomain(strings.Repeat("a", 64))
+	assert.Error(t, err)
+	_, err = r.WithTLD("")
+	assert.EqualError(t, err, "with tld: missing top level domain")
+}
+
+func TestRecordParentChild(t *testing.T) {
+	r := &Record{Subdomain: "a.b", Name: "example", TLD: "co.uk", Port: "443", ServiceLabels: []string{"_acme", "_tls"}, Wildcard: true, Warnings: []string{"stale"}}
+	var hosts []string
+	for p, ok := r, true; ok; p, ok = p.Parent() {
+		hosts = append(hosts, p.Hostname())
+	}
+	assert.Equal(t, []string{"*._acme._tls.a.b.example.co.uk", "_acme._tls.a.b.example.co.uk", "_tls.a.b.example.co.uk", "a.b.example.co.uk", "b.example.co.uk", "example.co.uk"}, hosts)
+	parent, ok := r.Parent()
+	assert.True(t, ok)
+	assert.Equal(t, &Record{Subdomain: "a.b", Name: "example", TLD: "co.uk", Port: "443", ServiceLabels: []string{"_acme", "_tls"}}, parent)
+	assert.True(t, r.Wildcard)
+	_, ok = (&Record{Name: "example", TLD: "com"}).Parent()
+	assert.False(t, ok)
+
+	apex := &Record{Name: "example", TLD: "com"}
+	www, err := apex.Child("WWW")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "www", Name: "example", TLD: "com"}, www)
+	api, err := www.Child("api")
+	assert.NoError(t, err)
+	assert.Equal(t, "api.www", api.Subdomain)
+	wildcard, err := api.Child("*")
+	assert.NoError(t, err)
+	assert.Equal(t, "*.api.www.example.com", wildcard.Hostname())
+	_, err = wildcard.Child("x")
+	assert.EqualError(t, err, `child: "*.api.www.example.com" is a wildcard`)
+
+	svc := &Record{Subdomain: "mail", Name: "example", TLD: "com", ServiceLabels: []string{"_tcp"}}
+	srv, err := svc.Child("_imap")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"_imap", "_tcp"}, srv.ServiceLabels)
+	plain, err := svc.Child("x")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "x._tcp.mail", Name: "example", TLD: "com"}, plain)
+
+	for _, label := range []string{"", "a.b", "my host", strings.Repeat("a", 64)} {
+		_, err = apex.Child(label)
+		assert.Error(t, err, label)
+	}
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// Node is a node of the tree built by BuildTree. The root has no label, its
+// children are registrable domains and every level below adds one label.
+type Node struct {
+	// Label is the label the node adds to its parent, the whole registrable
+	// domain for the children of the root, empty for the root
+	Label string
+	// Name is the hostname of the node, empty for the root
+	Name string
+	// Hosts is how many input hosts name exactly this node
+	Hosts int
+	// Count is how many input hosts name this node or a node below it
+	Count int
+	// Children are ordered by label
+	Children []*Node
+	// Unparsed holds, on the root only, the input hosts that did not parse
+	Unparsed []string
+
+	index map[string]*Node
+}
+
+// BuildTree arranges hosts into a tree rooted at their registrable domains,
+// with a child node per subdomain label, so "a.b.example.com" becomes
+// example.com → b.example.com → a.b.example.com. Service labels count as
+// subdomain labels, names are lowercased and duplicates are counted.
+func (d *Domain) BuildTree(hosts []string) *Node {
+	root := &Node{}
+	for _, host := range hosts {
+		rec, err := d.Parse(host)
+		if err != nil {
+			root.Unparsed = append(root.Unparsed, host)
+			continue
+		}
+		root.Count++
+		apex := strings.ToLower(rec.Name + "." + rec.TLD)
+		n := root.child(apex, apex)
+		n.Count++
+		var sub []string
+		sub = append(sub, rec.ServiceLabels...)
+		if rec.Subdomain != "" {
+			sub = append(sub, strings.Split(rec.Subdomain, ".")...)
+		}
+		for i := len(sub) - 1; i >= 0; i-- {
+			label := strings.ToLower(sub[i])
+			n = n.child(label, label+"."+n.Name)
+			n.Count++
+		}
+		n.Hosts++
+	}
+	root.sortChildren()
+	return root
+}
+
+// child returns the child of n with label, creating it if needed
+func (n *Node) child(label, name string) *Node {
+	if c, ok := n.index[label]; ok {
+		return c
+	}
+	if n.index == nil {
+		n.index = make(map[string]*Node)
+	}
+	c := &Node{Label: label, Name: name}
+	n.index[label] = c
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// sortChildren orders the children of every node by label
+func (n *Node) sortChildren() {
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Label < n.Children[j].Label })
+	for _, c := range n.Children {
+		c.sortChildren()
+	}
+}
+
+// Child returns the child with the given label, or nil
+func (n *Node) Child(label string) *Node {
+	return n.index[strings.ToLower(label)]
+}
+
+// Find returns the node of a hostname below n, or nil if the tree has no
+// such node. On the root, name is looked up from its registrable domain.
+func (n *Node) Find(name string) *Node {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if n.Name == name {
+		return n
+	}
+	for _, c := range n.Children {
+		if name == c.Name || strings.HasSuffix(name, "."+c.Name) {
+			return c.Find(name)
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for n and every node below it, parents before children,
+// with the depth below n. Returning false from fn skips the children of
+// that node.
+func (n *Node) Walk(fn func(n *Node, depth int) bool) {
+	n.walk(fn, 0)
+}
+
+func (n *Node) walk(fn func(*Node, int) bool, depth int) {
+	if !fn(n, depth) {
+		return
+	}
+	for _, c := range n.Children {
+		c.walk(fn, depth+1)
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTree(t *testing.T) {
+	ex := newTestDomain(t)
+	root := ex.BuildTree([]string{
+		"www.example.com",
+		"db.api.example.com",
+		"API.example.com",
+		"www.example.com",
+		"example.co.uk",
+		"not a host",
+	})
+	assert.Equal(t, 5, root.Count)
+	assert.Equal(t, []string{"not a host"}, root.Unparsed)
+
+	var lines []string
+	root.Walk(func(n *Node, depth int) bool {
+		if depth > 0 {
+			lines = append(lines, fmt.Sprintf("%s%s %d/%d", strings.Repeat("  ", depth-1), n.Label, n.Hosts, n.Count))
+		}
+		return true
+	})
+	assert.Equal(t, []string{
+		"example.co.uk 1/1",
+		"example.com 0/4",
+		"  api 1/2",
+		"    db 1/1",
+		"  www 2/2",
+	}, lines)
+
+	api := root.Find("API.example.com")
+	if assert.NotNil(t, api) {
+		assert.Equal(t, "api.example.com", api.Name)
+		assert.Equal(t, "db.api.example.com", api.Child("db").Name)
+	}
+	assert.Nil(t, root.Find("mail.example.com"))
+	assert.Nil(t, root.Child("example.net"))
+
+	var apexes []string
+	root.Walk(func(n *Node, depth int) bool {
+		if depth == 1 {
+			apexes = append(apexes, n.Name)
+		}
+		return depth < 1
+	})
+	assert.Equal(t, []string{"example.co.uk", "example.com"}, apexes)
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// URL builds a URL for the record's host (and port, if one was parsed) from a
+// scheme such as "https" and a path. The path must be empty or absolute and
+// may carry a query and fragment, "/login?next=%2F" for example. Wildcard
+// records name no single host and return an error.
+func (r *Record) URL(scheme, path string) (*url.URL, error) {
+	if !validScheme(scheme) {
+		return nil, fmt.Errorf("url: invalid scheme \"%s\"", scheme)
+	}
+	if path != "" && !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("url: path \"%s\" must start with \"/\"", path)
+	}
+	if r.Name == "" || r.TLD == "" {
+		return nil, fmt.Errorf("url: record has no domain name")
+	}
+	if r.Wildcard {
+		return nil, fmt.Errorf("url: wildcard record \"%s\" has no single host", r.host())
+	}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("url: invalid path \"%s\": %v", path, err)
+	}
+	if ref.Scheme != "" || ref.Host != "" || ref.User != nil {
+		return nil, fmt.Errorf("url: path \"%s\" cannot contain a scheme or host", path)
+	}
+	host := r.host()
+	if r.Port != "" {
+		host = net.JoinHostPort(host, r.Port)
+	}
+	return &url.URL{
+		Scheme:   strings.ToLower(scheme),
+		Host:     host,
+		Path:     ref.Path,
+		RawPath:  ref.RawPath,
+		RawQuery: ref.RawQuery,
+		Fragment: ref.Fragment,
+	}, nil
+}
+
+// validScheme checks a scheme against the RFC 3986 grammar
+// ALPHA *( ALPHA / DIGIT / "+" / "-" / "." )
+func validScheme(scheme string) bool {
+	if scheme == "" {
+		return false
+	}
+	for i := 0; i < len(scheme); i++ {
+		c := scheme[i]
+		switch {
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '+' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
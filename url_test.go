@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordURL(t *testing.T) {
+	tests := []struct {
+		r            Record
+		scheme, path string
+		o            string
+	}{
+		{r: Record{Subdomain: "www", Name: "example", TLD: "com"}, scheme: "https", path: "/login", o: "https://www.example.com/login"},
+		{r: Record{Name: "example", TLD: "co.uk", Port: "8443"}, scheme: "HTTPS", path: "", o: "https://example.co.uk:8443"},
+		{r: Record{Name: "example", TLD: "com"}, scheme: "http", path: "/a%20b?q=1#top", o: "http://example.com/a%20b?q=1#top"},
+		{r: Record{Name: "example", TLD: "com"}, scheme: "ht tp", path: "/"},
+		{r: Record{Name: "example", TLD: "com"}, scheme: "1http", path: "/"},
+		{r: Record{Name: "example", TLD: "com"}, scheme: "https", path: "login"},
+		{r: Record{Name: "example", TLD: "com"}, scheme: "https", path: "//evil.com/x"},
+		{r: Record{}, scheme: "https", path: "/"},
+		{r: Record{Name: "example", TLD: "com", Wildcard: true}, scheme: "https", path: "/x"},
+	}
+	for _, ts := range tests {
+		u, err := ts.r.URL(ts.scheme, ts.path)
+		if ts.o == "" {
+			assert.Error(t, err, ts.scheme+" "+ts.path)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, ts.o, u.String())
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Lynx Security LLC. All rights reserved.
+// Use of this source code is governed by an MIT license
+// that can be found in the LICENSE file
+
+/*
+Package domain is version 2 of github.com/lynxsecurity/domain. It holds the
+API changes that could not be made compatibly in v1 and shares the v1 parser,
+so both versions return the same results:
+
+  - New takes the cache file as an option instead of a required argument
+  - Levels returns an error instead of an empty slice
+  - Record.String returns the plain hostname, without the leading dot v1
+    gives records that have no subdomain
+
+A Domain embeds the v1 Domain, so every other method is the v1 one and the
+embedded value can be handed to code still on v1. Importers can move one call
+site at a time:
+
+	d, err := domain.New(domain.WithCacheFile("/tmp/tld.cache"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	record, err := d.Parse("www.hackerone.com")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(record)
+	legacy(d.Domain) // a *v1.Domain
+*/
+package domain
+
+import (
+	v1 "github.com/lynxsecurity/domain"
+)
+
+// Domain parses domain names against the public suffix list
+type Domain struct {
+	*v1.Domain
+}
+
+// Record is a parsed domain name
+type Record struct {
+	v1.Record
+}
+
+// String returns the hostname of the record, "example.com" for a record
+// without a subdomain
+func (r *Record) String() string {
+	return r.Hostname()
+}
+
+// ParseError is the error Parse returns, see the v1 ParseError
+type ParseError = v1.ParseError
+
+// Option configures New
+type Option func(*config)
+
+// config is the configuration Option values build
+type config struct {
+	cacheFile string
+	options   []v1.Option
+}
+
+// WithCacheFile sets the file the suffix list is cached in. Without it one of
+// the v1 options WithInMemory, WithBackend or WithProvider must be given.
+func WithCacheFile(path string) Option {
+	return func(c *config) {
+		c.cacheFile = path
+	}
+}
+
+// WithOptions applies v1 options, such as v1.WithServiceLabels or
+// v1.WithInMemory, which keep working unchanged in v2
+func WithOptions(opts ...v1.Option) Option {
+	return func(c *config) {
+		c.options = append(c.options, opts...)
+	}
+}
+
+// New creates a Domain
+func New(opts ...Option) (*Domain, error) {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	d, err := v1.New(c.cacheFile, c.options...)
+	if err != nil {
+		return nil, err
+	}
+	return &Domain{Domain: d}, nil
+}
+
+// Parse parses a domain name into a Record, see the v1 Parse
+func (d *Domain) Parse(host string, opts ...v1.ParseOption) (*Record, error) {
+	rec, err := d.Domain.Parse(host, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Record{Record: *rec}, nil
+}
+
+// Levels returns all subdomain levels of host, longest first, down to the
+// registrable domain, or the error Parse returns for host
+func (d *Domain) Levels(host string) ([]string, error) {
+	return d.LevelsE(host)
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/lynxsecurity/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordString(t *testing.T) {
+	tests := []struct {
+		i Record
+		o string
+	}{
+		{i: Record{v1.Record{Subdomain: "www", Name: "example", TLD: "com"}}, o: "www.example.com"},
+		{i: Record{v1.Record{Name: "Example", TLD: "co.uk"}}, o: "example.co.uk"},
+		{i: Record{v1.Record{Name: "example", TLD: "com", Wildcard: true}}, o: "*.example.com"},
+	}
+	for _, ts := range tests {
+		assert.Equal(t, ts.o, ts.i.String())
+	}
+}
+
+func TestDomain(t *testing.T) {
+	d, err := New(WithOptions(v1.WithProvider(v1.StaticProvider{"com", "uk", "co.uk"}), v1.WithServiceLabels()))
+	assert.NoError(t, err)
+	r, err := d.Parse("_dmarc.Example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{v1.Record{Name: "example", TLD: "co.uk", ServiceLabels: []string{"_dmarc"}}}, r)
+	assert.Equal(t, "_dmarc.example.co.uk", r.String())
+
+	levels, err := d.Levels("www.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"www.example.com", "example.com"}, levels)
+	_, err = d.Levels("naan.example")
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+
+	// the embedded v1 Domain parses the same way
+	legacy, err := d.Domain.Parse("_dmarc.Example.co.uk")
+	assert.NoError(t, err)
+	assert.Equal(t, r.Record, *legacy)
+
+	_, err = New()
+	assert.Error(t, err)
+
+	cache := filepath.Join(t.TempDir(), "tld.cache")
+	assert.NoError(t, os.WriteFile(cache, []byte("com\n"), 0644))
+	d, err = New(WithCacheFile(cache), WithOptions(v1.WithOffline()))
+	assert.NoError(t, err)
+	r, err = d.Parse("example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", r.String())
+}
@@ -0,0 +1,18 @@
+module github.com/lynxsecurity/domain/v2
+
+go 1.23
+
+require (
+	github.com/lynxsecurity/domain v0.0.0
+	github.com/stretchr/testify v1.5.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.30.0 // indirect
+	golang.org/x/text v0.19.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
+
+replace github.com/lynxsecurity/domain => ../
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Violation is one problem Validate found in a name
+type Violation struct {
+	Code ParseErrorCode
+	// Label is the label the problem is in, empty when the problem is not
+	// confined to one label
+	Label string
+	// Offset is the byte offset of the problem in the validated name
+	Offset  int
+	Message string
+}
+
+// ValidationReport lists every problem of a name, see Validate
+type ValidationReport struct {
+	// Host is the name that was checked, lowercased and with any port
+	// removed, offsets of the violations point into it
+	Host       string
+	Violations []Violation
+}
+
+// Valid reports whether the name has no violations
+func (r *ValidationReport) Valid() bool {
+	return len(r.Violations) == 0
+}
+
+// Messages returns the messages of the violations, in the order found
+func (r *ValidationReport) Messages() []string {
+	msgs := make([]string, len(r.Violations))
+	for i, v := range r.Violations {
+		msgs[i] = v.Message
+	}
+	return msgs
+}
+
+// Validate checks host against every rule Parse applies plus the DNS length
+// limits, and reports all violations instead of stopping at the first one,
+// for forms that show every problem at once: characters that cannot appear
+// in a hostname, invisible characters with WithRejectInvisible, a missing
+// dot, empty labels, labels over 63 and names over 253 characters, and a
+// suffix that is unknown or has no name under it. A name that passes those
+// is then parsed, so the checks of options such as WithIDNAProfile,
+// WithRootZone or WithSpecialUse and of wildcard names add their violation
+// too, and a name with a valid report parses.
+func (d *Domain) Validate(host string) *ValidationReport {
+	r := &ValidationReport{Host: host}
+	add := func(code ParseErrorCode, offset int, msg string) {
+		r.Violations = append(r.Violations, Violation{Code: code, Label: labelAt(r.Host, offset), Offset: offset, Message: msg})
+	}
+	if err := d.checkInputLength(host); err != nil {
+		r.Host = err.(*ParseError).Input
+		add(ErrCodeInputTooLarge, 0, err.(*ParseError).msg)
+		return r
+	}
+	if d.opts.lenient {
+		host = lenientHost(host)
+	}
+	host, _, err := splitPort(normalizeDots(host))
+	if err != nil {
+		pe := err.(*ParseError)
+		r.Host = pe.Input
+		add(pe.Code, pe.Offset, pe.msg)
+		return r
+	}
+	if !d.opts.rejectInvisible {
+		host = stripInvisible(host)
+	}
+	r.Host = foldCase(host, d.opts.casePolicy)
+	if err := d.checkLabelCount(r.Host); err != nil {
+		add(ErrCodeInputTooLarge, 0, err.(*ParseError).msg)
+		return r
+	}
+
+	for i, c := range r.Host {
+		if invisible(c) {
+			add(ErrCodeInvisibleCharacter, i, fmt.Sprintf("domain name cannot contain invisible character %U", c))
+			continue
+		}
+		for _, bad := range badChars {
+			if c == bad {
+				add(ErrCodeInvalidCharacter, i, fmt.Sprintf("domain name cannot contain \"%c\"", c))
+			}
+		}
+		if c == utf8.RuneError {
+			add(ErrCodeInvalidCharacter, i, "domain name is not valid UTF-8")
+		}
+	}
+	if !strings.ContainsRune(r.Host, '.') {
+		add(ErrCodeNoDot, 0, "domain name must contain at least one \".\"")
+	}
+	labels := SplitLabels(r.Host)
+	empty := false
+	offset := 0
+	for _, label := range labels {
+		if label == "" {
+			empty = true
+			add(ErrCodeEmptyLabel, offset, "domain name cannot contain an empty label")
+		} else if ascii, err := toASCII(label); err == nil && len(ascii) > maxLabelLength {
+			add(ErrCodeLabelTooLong, offset, fmt.Sprintf("label \"%s\" is longer than %d characters", label, maxLabelLength))
+		}
+		offset += len(label) + 1
+	}
+	if ascii, err := toASCII(strings.TrimSuffix(r.Host, ".")); err == nil && len(ascii) > maxNameLength {
+		add(ErrCodeNameTooLong, 0, fmt.Sprintf("name is longer than %d characters", maxNameLength))
+	}
+
+	if empty || len(labels) < 2 {
+		return r
+	}
+	start, _, ok := d.matchRule(labels, nil)
+	switch {
+	case !ok && !d.opts.fallbackTLD:
+		add(ErrCodeUnknownTLD, lastLabelOffset(r.Host), "top level domain does not exist")
+	case ok && start == 0:
+		add(ErrCodeMissingName, 0, "missing domain name")
+	}
+	if r.Valid() {
+		var pe *ParseError
+		if _, err := d.Parse(r.Host); errors.As(err, &pe) {
+			add(pe.Code, pe.Offset, pe.msg)
+		}
+	}
+	return r
+}
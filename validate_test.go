@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	ex := newTestDomain(t)
+
+	r := ex.Validate("WWW.Example.co.uk:443")
+	assert.True(t, r.Valid())
+	assert.Equal(t, "www.example.co.uk", r.Host)
+
+	r = ex.Validate("my site..exa(mple)." + strings.Repeat("a", 64) + ".nonexist")
+	assert.False(t, r.Valid())
+	assert.Equal(t, []Violation{
+		{Code: ErrCodeInvalidCharacter, Label: "my site", Offset: 2, Message: `domain name cannot contain " "`},
+		{Code: ErrCodeInvalidCharacter, Label: "exa(mple)", Offset: 12, Message: `domain name cannot contain "("`},
+		{Code: ErrCodeInvalidCharacter, Label: "exa(mple)", Offset: 17, Message: `domain name cannot contain ")"`},
+		{Code: ErrCodeEmptyLabel, Label: "", Offset: 8, Message: "domain name cannot contain an empty label"},
+		{Code: ErrCodeLabelTooLong, Label: strings.Repeat("a", 64), Offset: 19, Message: `label "` + strings.Repeat("a", 64) + `" is longer than 63 characters`},
+	}, r.Violations)
+
+	r = ex.Validate("example.nonexist")
+	assert.Equal(t, []string{"top level domain does not exist"}, r.Messages())
+	assert.Equal(t, "nonexist", r.Violations[0].Label)
+
+	r = ex.Validate(strings.Repeat("abcdefghi.", 26) + "com")
+	assert.Equal(t, []string{"name is longer than 253 characters"}, r.Messages())
+
+	assert.Equal(t, []string{"missing domain name"}, ex.Validate("co.uk").Messages())
+	assert.Equal(t, []string{`domain name must contain at least one "."`}, ex.Validate("localhost").Messages())
+	assert.Equal(t, ErrCodeIPAddress, ex.Validate("192.0.2.1:80").Violations[0].Code)
+	assert.Equal(t, ErrCodeInvalidPort, ex.Validate("example.com:http").Violations[0].Code)
+}
+
+func TestValidateParseOptions(t *testing.T) {
+	strict := newTestDomain(t, WithIDNAProfile(IDNARegistration))
+	r := strict.Validate("ab--c.example.com")
+	if assert.Len(t, r.Violations, 1) {
+		assert.Equal(t, ErrCodeIDNA, r.Violations[0].Code)
+		assert.Equal(t, "ab--c", r.Violations[0].Label)
+	}
+	assert.True(t, newTestDomain(t).Validate("ab--c.example.com").Valid())
+
+	ex := newTestDomain(t)
+	assert.True(t, ex.Validate("*.example.co.uk").Valid())
+	r = ex.Validate("*.co.uk")
+	if assert.Len(t, r.Violations, 1) {
+		assert.Equal(t, ErrCodeMissingName, r.Violations[0].Code)
+	}
+
+	reverse := newTestDomain(t, WithReverseDNS())
+	assert.True(t, reverse.Validate("4.3.2.1.in-addr.arpa").Valid())
+	for _, host := range []string{"ab--c.example.com", "*.co.uk", "www.example.com", "4.3.2.1.in-addr.arpa"} {
+		for _, d := range []*Domain{strict, ex, reverse} {
+			_, err := d.Parse(host)
+			assert.Equal(t, err == nil, d.Validate(host).Valid(), host)
+		}
+	}
+}
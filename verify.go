@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// Verifier checks a downloaded suffix list, exactly as it was served, before
+// it is accepted into the cache. A list that fails verification is treated
+// like a failed download: the next mirror is tried and the cache is left
+// alone. Verifiers for detached signatures, such as PGP, plug in here.
+type Verifier interface {
+	Verify(ctx context.Context, list []byte) error
+}
+
+// VerifierFunc adapts a function to a Verifier
+type VerifierFunc func(ctx context.Context, list []byte) error
+
+// Verify calls f
+func (f VerifierFunc) Verify(ctx context.Context, list []byte) error {
+	return f(ctx, list)
+}
+
+// ChecksumError is returned, wrapped, when a downloaded list does not match
+// the SHA-256 sum given to WithChecksum or published at WithChecksumURL
+type ChecksumError struct {
+	// Want and Got are the hex encoded expected and actual sums
+	Want, Got string
+}
+
+// Error describes the mismatch
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("suffix list checksum mismatch: want sha256 %s, got %s", e.Want, e.Got)
+}
+
+// WithVerifier checks every downloaded list with v, it can be given more
+// than once
+func WithVerifier(v Verifier) Option {
+	return func(o *options) {
+		o.verifiers = append(o.verifiers, v)
+	}
+}
+
+// WithChecksum only accepts a downloaded list whose SHA-256 sum is sum, hex
+// encoded, for a list pinned to a known snapshot
+func WithChecksum(sum string) Option {
+	return func(o *options) {
+		sum = strings.ToLower(strings.TrimSpace(sum))
+		if !isSHA256(sum) {
+			o.errs = append(o.errs, fmt.Sprintf("checksum %q is not a hex encoded SHA-256 sum", sum))
+			return
+		}
+		o.verifiers = append(o.verifiers, VerifierFunc(func(ctx context.Context, list []byte) error {
+			return checkSum(list, sum)
+		}))
+	}
+}
+
+// WithChecksumURL only accepts a downloaded list whose SHA-256 sum matches
+// the one published at url, fetched alongside every download. The file may
+// hold the bare sum or sha256sum output, the first field is used.
+func WithChecksumURL(url string) Option {
+	return func(o *options) {
+		o.checksumURL = url
+	}
+}
+
+// verifyList runs the checksum URL and every Verifier of o on list
+func (o *options) verifyList(ctx context.Context, list []byte) error {
+	if o.checksumURL != "" {
+		sum, err := fetchChecksum(ctx, HTTPDownloader{URL: o.checksumURL, Client: o.httpClient()})
+		if err != nil {
+			return err
+		}
+		if err := checkSum(list, sum); err != nil {
+			return fmt.Errorf("Could not verify suffix list: %w", err)
+		}
+	}
+	for _, v := range o.verifiers {
+		if err := v.Verify(ctx, list); err != nil {
+			return fmt.Errorf("Could not verify suffix list: %w", err)
+		}
+	}
+	return nil
+}
+
+// fetchChecksum downloads a published sum
+func fetchChecksum(ctx context.Context, src Downloader) (string, error) {
+	body, err := src.Fetch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("Could not download checksum: %v", err)
+	}
+	defer body.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(body, 4096))
+	if err != nil {
+		return "", fmt.Errorf("Could not download checksum: %v", err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || !isSHA256(strings.ToLower(fields[0])) {
+		return "", fmt.Errorf("Could not download checksum: no SHA-256 sum found")
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// checkSum compares the SHA-256 sum of list to want
+func checkSum(list []byte, want string) error {
+	sum := sha256.Sum256(list)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return &ChecksumError{Want: want, Got: got}
+	}
+	return nil
+}
+
+// isSHA256 checks that s is a lowercase hex encoded SHA-256 sum
+func isSHA256(s string) bool {
+	if len(s) != 2*sha256.Size {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
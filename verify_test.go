@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyDownload(t *testing.T) {
+	const good, tampered = "// VERSION: good\ncom\nco.uk\n", "// VERSION: good\ncom\n"
+	sum := sha256.Sum256([]byte(good))
+	want := hex.EncodeToString(sum[:])
+
+	o := newOptions([]Option{WithDownloader(&cannedDownloader{list: good}), WithChecksum(want)})
+	var list bytes.Buffer
+	assert.NoError(t, downloadList(&list, &o))
+	assert.Contains(t, list.String(), "co.uk\n")
+
+	o = newOptions([]Option{WithDownloader(&cannedDownloader{list: tampered}), WithChecksum(want)})
+	list.Reset()
+	err := downloadList(&list, &o)
+	var mismatch *ChecksumError
+	if assert.True(t, errors.As(err, &mismatch)) {
+		assert.Equal(t, want, mismatch.Want)
+	}
+	assert.Empty(t, list.String())
+
+	// a tampered mirror is skipped in favour of the next one
+	served := map[string]string{"/primary": tampered, "/mirror": good, "/sum": want + "  public_suffix_list.dat\n"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, served[r.URL.Path])
+	}))
+	defer srv.Close()
+	o = newOptions([]Option{WithListURL(srv.URL + "/primary"), WithMirrors(srv.URL + "/mirror"), WithChecksumURL(srv.URL + "/sum")})
+	assert.NoError(t, o.validate("/tmp/tld.cache"))
+	list.Reset()
+	assert.NoError(t, downloadList(&list, &o))
+	assert.Contains(t, list.String(), "co.uk\n")
+
+	served["/sum"] = "not a sum"
+	err = downloadList(&list, &o)
+	assert.Contains(t, err.Error(), "no SHA-256 sum found")
+
+	rejected := errors.New("bad signature")
+	o = newOptions([]Option{WithDownloader(&cannedDownloader{list: good}), WithVerifier(VerifierFunc(func(ctx context.Context, list []byte) error {
+		return rejected
+	}))})
+	o.sleep = func(time.Duration) {}
+	err = downloadList(&list, &o)
+	assert.True(t, errors.Is(err, rejected))
+
+	o = newOptions([]Option{WithChecksum("abc"), WithChecksumURL("ftp://example.com/sum")})
+	err = o.validate("/tmp/tld.cache")
+	if assert.IsType(t, &OptionsError{}, err) {
+		assert.Equal(t, []string{`checksum "abc" is not a hex encoded SHA-256 sum`, `checksum URL "ftp://example.com/sum" is not an http or https URL`}, err.(*OptionsError).Problems)
+	}
+}
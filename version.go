@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ListVersion identifies the public suffix list a Domain was built from
+type ListVersion struct {
+	// Version is the VERSION header of the list, usually its publication date
+	Version string
+	// Commit is the COMMIT header of the list, the upstream git revision
+	Commit string
+	// Downloaded is when the list was fetched from publicsuffix.org, it is
+	// zero for caches written before the download time was recorded
+	Downloaded time.Time
+}
+
+// ListVersion returns the version metadata of the loaded suffix list
+func (d *Domain) ListVersion() ListVersion {
+	return d.snapshot().version
+}
+
+// String formats the version for logs and reports
+func (v ListVersion) String() string {
+	var parts []string
+	if v.Version != "" {
+		parts = append(parts, "version "+v.Version)
+	}
+	if v.Commit != "" {
+		parts = append(parts, "commit "+v.Commit)
+	}
+	if !v.Downloaded.IsZero() {
+		parts = append(parts, "downloaded "+v.Downloaded.Format(time.RFC3339))
+	}
+	if len(parts) == 0 {
+		return "unknown version"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// cache header keys, VERSION and COMMIT are copied from the upstream list
+const (
+	headerVersion    = "VERSION:"
+	headerCommit     = "COMMIT:"
+	headerDownloaded = "DOWNLOADED:"
+)
+
+// parseHeader picks version metadata out of a "//" comment line
+func (v *ListVersion) parseHeader(line string) {
+	line = strings.TrimSpace(strings.TrimPrefix(line, "//"))
+	switch {
+	case strings.HasPrefix(line, headerVersion):
+		v.Version = strings.TrimSpace(line[len(headerVersion):])
+	case strings.HasPrefix(line, headerCommit):
+		v.Commit = strings.TrimSpace(line[len(headerCommit):])
+	case strings.HasPrefix(line, headerDownloaded):
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(line[len(headerDownloaded):]))
+		if err == nil {
+			v.Downloaded = t
+		}
+	}
+}
+
+// writeHeader writes the metadata as cache comment lines
+func (v *ListVersion) writeHeader(w io.Writer) {
+	if v.Version != "" {
+		fmt.Fprintf(w, "// %s %s\n", headerVersion, v.Version)
+	}
+	if v.Commit != "" {
+		fmt.Fprintf(w, "// %s %s\n", headerCommit, v.Commit)
+	}
+	if !v.Downloaded.IsZero() {
+		fmt.Fprintf(w, "// %s %s\n", headerDownloaded, v.Downloaded.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListVersionRoundTrip(t *testing.T) {
+	raw := `// This Source Code Form is subject to the terms of the Mozilla Public
+// VERSION: 2026-10-01_09-12-44_UTC
+// COMMIT: 3f1c0e2a9b
+// ===BEGIN ICANN DOMAINS===
+
+com
+co.uk
+`
+	downloaded := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	var cache bytes.Buffer
+	assert.NoError(t, writeList(&cache, strings.NewReader(raw), downloaded))
+
+	rules, version := readRules(&cache)
+	assert.Equal(t, []string{"com", "co.uk"}, ruleTexts(rules))
+	assert.Equal(t, ListVersion{
+		Version:    "2026-10-01_09-12-44_UTC",
+		Commit:     "3f1c0e2a9b",
+		Downloaded: downloaded,
+	}, version)
+	assert.Equal(t, "version 2026-10-01_09-12-44_UTC, commit 3f1c0e2a9b, downloaded 2026-10-14T12:00:00Z", version.String())
+}
+
+func TestListVersionLegacyCache(t *testing.T) {
+	d := newDomain("", strings.NewReader("com\nco.uk\n"), &options{})
+	assert.Equal(t, ListVersion{}, d.ListVersion())
+	assert.Equal(t, "unknown version", d.ListVersion().String())
+}
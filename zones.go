@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ZoneSource is the name of the Source holding the zones given to WithZones
+// and WithZonesFile
+const ZoneSource = "zones"
+
+// WithZones treats internal zones such as "internal.corp.example.com" as
+// public suffixes, so "db01.payments.internal.corp.example.com" parses with
+// Name "payments" and Levels stop at the zone. The zones are merged like a
+// Source named ZoneSource with the highest priority.
+func WithZones(zones ...string) Option {
+	return func(o *options) {
+		o.zones = append(o.zones, zones...)
+	}
+}
+
+// WithZonesFile reads zones for WithZones from a file holding one zone per
+// line, blank lines and lines starting with "#" or "//" are skipped
+func WithZonesFile(path string) Option {
+	return func(o *options) {
+		zones, err := readZones(path)
+		if err != nil {
+			o.errs = append(o.errs, fmt.Sprintf("zones file: %v", err))
+		}
+		o.zones = append(o.zones, zones...)
+	}
+}
+
+// readZones reads the zones of a zones file
+func readZones(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var zones []string
+	scan := bufio.NewScanner(f)
+	for scan.Scan() {
+		line := strings.TrimSpace(scan.Text())
+		if line != "" && !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "//") {
+			zones = append(zones, line)
+		}
+	}
+	return zones, scan.Err()
+}
+
+// zoneSource returns the zones as a Source, cleaned of surrounding dots
+func zoneSource(zones []string) Source {
+	src := Source{Name: ZoneSource, Priority: int(^uint(0) >> 1)}
+	for _, z := range zones {
+		src.Rules = append(src.Rules, strings.ToLower(strings.Trim(strings.TrimSpace(z), ".")))
+	}
+	return src
+}
+
+// zoneProblems checks that every zone is a plain name with no empty labels
+func zoneProblems(zones []string) []string {
+	var problems []string
+	for _, z := range zoneSource(zones).Rules {
+		if z == "" || strings.Contains(z, "..") || strings.ContainsAny(z, " */!") {
+			problems = append(problems, fmt.Sprintf("zone %q is not a valid domain name", z))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZones(t *testing.T) {
+	dir, err := ioutil.TempDir("", "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "zones.txt")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("# internal zones\n\nlab.example.net\n"), 0644))
+
+	o := newOptions([]Option{WithZones(".Internal.Corp.Example.com"), WithZonesFile(file)})
+	assert.NoError(t, o.validate("/tmp/tld.cache"))
+	d := newDomain("", strings.NewReader("com\nnet\n"), &o)
+
+	r, err := d.Parse("db01.payments.internal.corp.example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, &Record{Subdomain: "db01", Name: "payments", TLD: "internal.corp.example.com"}, r)
+	assert.Equal(t, []string{"db01.payments.internal.corp.example.com", "payments.internal.corp.example.com"}, d.Levels("db01.payments.internal.corp.example.com"))
+	r, err = d.Parse("gpu.lab.example.net")
+	assert.NoError(t, err)
+	assert.Equal(t, "lab.example.net", r.TLD)
+	res, ok := d.Resolve("lab.example.net")
+	assert.True(t, ok)
+	assert.Equal(t, ZoneSource, res.Source)
+
+	o = newOptions([]Option{WithZones("a..b", "*.corp"), WithZonesFile(filepath.Join(dir, "missing.txt"))})
+	err = o.validate("/tmp/tld.cache")
+	if assert.IsType(t, &OptionsError{}, err) {
+		problems := err.(*OptionsError).Problems
+		assert.Len(t, problems, 3)
+		assert.Contains(t, problems[0], "zones file: open ")
+		assert.Equal(t, []string{`zone "a..b" is not a valid domain name`, `zone "*.corp" is not a valid domain name`}, problems[1:])
+	}
+}